@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <pool>",
+	Short: "Recover the lease file for a claim held by this identity",
+	Long: `Looks up the active claim held by this holder's identity in the pool and writes
+its lease file, so read, env, renew and release work again. Use it in a later
+CI job that shares the claiming job's identity (e.g. CLAIMENV_HOLDER or the MR)
+but didn't receive the lease file as an artifact.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		poolName := args[0]
+
+		lf, err := eng.Attach(cmd.Context(), poolName)
+		if errors.Is(err, lockstore.ErrLeaseNotFound) {
+			return fmt.Errorf("no active claim held by %s in pool %q", eng.Identity, poolName)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Don't overwrite the lease of some other claim in this pool
+		f, err := lease.ReadFile(eng.LeaseFile)
+		if err != nil {
+			return err
+		}
+		if err := checkNoLease(f, poolName, lf.LeaseID); err != nil {
+			return err
+		}
+
+		f.Put(lf)
+		if err := lease.WriteFile(eng.LeaseFile, f); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Attached to slot %q in pool %q (expires: %s)\n",
+			lf.SlotName, lf.Pool, lf.ExpiresAt.Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
@@ -3,39 +3,90 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/Kashuab/claimenv/internal/lease"
+	"github.com/Kashuab/claimenv/pkg/lease"
 	"github.com/spf13/cobra"
 )
 
+var (
+	claimAnnotations []string
+	claimPrintToken  bool
+)
+
 var claimCmd = &cobra.Command{
 	Use:   "claim <pool>",
 	Short: "Claim an available slot from a pool",
-	Args:  cobra.ExactArgs(1),
+	Long: `Claims an available slot from a pool and adds its lease to the lease file.
+Leases in several pools can be held at once, one per pool.
+
+With --print-token, no lease file is written; instead a lease token is printed
+on stdout. Pass it to later commands in the CLAIMENV_LEASE environment variable,
+e.g. through a CI dotenv artifact or into a container with a read-only
+filesystem.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		poolName := args[0]
 
-		// Refuse if there's already an active lease
-		if existing, err := lease.Load(eng.LeaseFile); err == nil {
-			return fmt.Errorf("already holding slot %q in pool %q (lease: %s). Release it first with: claimenv release",
-				existing.SlotName, existing.Pool, existing.LeaseID)
+		// Refuse if there's already an active lease in this pool; leases in
+		// other pools are kept alongside
+		f, err := lease.ReadFile(eng.LeaseFile)
+		if err != nil {
+			return err
+		}
+		if err := checkNoLease(f, poolName, ""); err != nil {
+			return err
 		}
 
-		lf, err := eng.Claim(cmd.Context(), poolName)
+		annotations, err := parseAnnotations(claimAnnotations)
 		if err != nil {
 			return err
 		}
 
-		if err := lease.Save(eng.LeaseFile, lf); err != nil {
+		lf, err := eng.Claim(cmd.Context(), poolName, annotations)
+		if err != nil {
 			return err
 		}
 
-		fmt.Fprintf(os.Stderr, "Claimed slot %q from pool %q (lease: %s, expires: %s)\n",
-			lf.SlotName, lf.Pool, lf.LeaseID, lf.ExpiresAt.Format("2006-01-02 15:04:05"))
+		if claimPrintToken {
+			token, err := lease.EncodeToken(lf)
+			if err != nil {
+				return err
+			}
+			fmt.Println(token)
+		} else {
+			f.Put(lf)
+			if err := lease.WriteFile(eng.LeaseFile, f); err != nil {
+				return err
+			}
+		}
+
+		// CI logs are widely readable, so the lease ID is left out
+		fmt.Fprintf(os.Stderr, "Claimed slot %q from pool %q (expires: %s)\n",
+			lf.SlotName, lf.Pool, lf.ExpiresAt.Format("2006-01-02 15:04:05"))
 		return nil
 	},
 }
 
+// parseAnnotations turns repeated key=value flags into a map.
+func parseAnnotations(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	annotations := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid annotation %q: expected key=value", pair)
+		}
+		annotations[key] = value
+	}
+	return annotations, nil
+}
+
 func init() {
+	claimCmd.Flags().StringArrayVar(&claimAnnotations, "annotate", nil, "attach key=value context to the claim (repeatable), e.g. --annotate mr=https://...")
+	claimCmd.Flags().BoolVar(&claimPrintToken, "print-token", false, "print a lease token for CLAIMENV_LEASE instead of writing the lease file")
 	rootCmd.AddCommand(claimCmd)
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/dashboard"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dashboardListen   string
+	dashboardCacheTTL time.Duration
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Serve a read-only web dashboard of every pool",
+	Long: `Serves a web page showing every pool in the config: slot states, holders,
+expiry times, annotations and a utilization sparkline for the last 24 hours.
+The same data is available as JSON on /api/pools. Secret values and lease IDs
+are never shown. Runs until interrupted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := dashboard.New(eng)
+		d.CacheTTL = dashboardCacheTTL
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		srv := &http.Server{Addr: dashboardListen, Handler: d}
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.ListenAndServe()
+		}()
+
+		fmt.Fprintf(os.Stderr, "Serving dashboard on %s\n", dashboardListen)
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	dashboardCmd.Flags().StringVar(&dashboardListen, "listen", ":8090", "address to serve the dashboard on")
+	dashboardCmd.Flags().DurationVar(&dashboardCacheTTL, "cache", 15*time.Second, "how long to reuse pool status between page loads")
+	rootCmd.AddCommand(dashboardCmd)
+}
@@ -3,9 +3,9 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 
-	"github.com/Kashuab/claimenv/internal/lease"
 	"github.com/spf13/cobra"
 )
 
@@ -15,26 +15,36 @@ var envNames bool
 var envCmd = &cobra.Command{
 	Use:   "env",
 	Short: "Dump all env vars from the claimed slot",
-	Long:  `Outputs all environment variables. Use eval $(claimenv env) to source them. Use --names to output GCP Secret Manager secret names instead of values.`,
-	Args:  cobra.NoArgs,
+	Long: `Outputs all environment variables. Use eval $(claimenv env) to source them. Use --names to output GCP Secret Manager secret names instead of values.
+When holding leases in several pools, their variables are merged; use --pool to
+output only one pool's.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lf, err := lease.Load(eng.LeaseFile)
+		f, err := readLeaseFile(cmd.Context())
 		if err != nil {
 			return err
 		}
 
-		var all map[string]string
+		// Merge the leases in pool order; a key defined by several pools takes
+		// the value of the last one
+		all := make(map[string]string)
+		from := make(map[string]string)
+		for _, lf := range selectedLeases(f) {
+			values := lf.Secrets
+			if !envNames {
+				// Secret names are in the lease; values need API calls
+				values, err = eng.ReadAll(cmd.Context(), lf)
+				if err != nil {
+					return err
+				}
+			}
 
-		if envNames {
-			// Output secret names from the lease, no API calls needed
-			all = make(map[string]string, len(lf.Secrets))
-			for k, v := range lf.Secrets {
+			for k, v := range values {
+				if prev, ok := from[k]; ok {
+					fmt.Fprintf(os.Stderr, "Warning: %s is defined by pools %q and %q; using %q\n", k, prev, lf.Pool, lf.Pool)
+				}
 				all[k] = v
-			}
-		} else {
-			all, err = eng.ReadAll(cmd.Context(), lf)
-			if err != nil {
-				return err
+				from[k] = lf.Pool
 			}
 		}
 
@@ -69,5 +79,6 @@ var envCmd = &cobra.Command{
 func init() {
 	envCmd.Flags().StringVar(&envFormat, "format", "export", "output format: export, dotenv, json")
 	envCmd.Flags().BoolVar(&envNames, "names", false, "output GCP Secret Manager secret names instead of values")
+	addPoolFlag(envCmd, "only output the lease in this pool")
 	rootCmd.AddCommand(envCmd)
 }
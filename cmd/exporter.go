@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exporterListen   string
+	exporterInterval time.Duration
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve Prometheus metrics for pool utilization",
+	Long: `Polls the status of every pool in the config and serves slot counts per state,
+lease ages, exhaustion counts and claim wait durations on /metrics. Runs until
+interrupted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exporterInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		reg := prometheus.NewRegistry()
+		x, err := exporter.New(eng, reg)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+		srv := &http.Server{Addr: exporterListen, Handler: mux}
+
+		go x.Run(ctx, exporterInterval)
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.ListenAndServe()
+		}()
+
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", exporterListen)
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	exporterCmd.Flags().StringVar(&exporterListen, "listen", ":9090", "address to serve metrics on")
+	exporterCmd.Flags().DurationVar(&exporterInterval, "interval", 30*time.Second, "how often to poll pool status")
+	rootCmd.AddCommand(exporterCmd)
+}
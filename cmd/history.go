@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySince string
+	historyJSON  bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <pool> [slot]",
+	Short: "Show the claim history of a pool or slot",
+	Long: `Lists claims, renewals, releases, expiries and revocations recorded for the
+slots of a pool, oldest first. Use it to trace which holders used a credential.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		poolName := args[0]
+		slotName := ""
+		if len(args) == 2 {
+			slotName = args[1]
+		}
+
+		since, err := parseSince(historySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+
+		events, err := eng.History(cmd.Context(), poolName, slotName, since)
+		if err != nil {
+			return err
+		}
+
+		if historyJSON {
+			data, err := json.MarshalIndent(events, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		return printHistoryTable(events)
+	},
+}
+
+func printHistoryTable(events []lockstore.Event) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSLOT\tEVENT\tHOLDER\tANNOTATIONS")
+
+	for _, ev := range events {
+		annotations := "-"
+		if len(ev.Annotations) > 0 {
+			annotations = formatAnnotations(ev.Annotations)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			ev.At.Format("2006-01-02 15:04:05"), ev.SlotName, ev.Kind, ev.Holder, annotations)
+	}
+
+	return w.Flush()
+}
+
+// parseSince turns a lookback like "7d" or "12h" into the earliest time to show.
+// Days are accepted in addition to Go duration units.
+func parseSince(s string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expected a duration like 7d or 12h, got %q", s)
+		}
+		return time.Now().AddDate(0, 0, -n), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a duration like 7d or 12h, got %q", s)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historySince, "since", "7d", "how far back to look, e.g. 7d or 12h")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "output as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/spf13/cobra"
+)
+
+// leasePool is the --pool selector shared by the commands that use the lease file.
+var leasePool string
+
+func addPoolFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringVar(&leasePool, "pool", "", usage)
+}
+
+// readLeaseFile reads the lease file, or the lease token in CLAIMENV_LEASE if
+// set, failing if it holds no lease in --pool (or no lease at all if --pool
+// isn't set).
+func readLeaseFile(ctx context.Context) (*lease.File, error) {
+	if os.Getenv(lease.TokenEnv) != "" {
+		return readLeaseToken(ctx)
+	}
+
+	f, err := lease.ReadFile(eng.LeaseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Leases) == 0 {
+		return nil, fmt.Errorf("no active lease (file not found: %s)", eng.LeaseFile)
+	}
+	if leasePool != "" {
+		if _, err := f.Get(leasePool); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// readLeaseToken returns a lease file holding the lease in CLAIMENV_LEASE. The
+// token only identifies the lease, so the rest is looked up in the lock store.
+func readLeaseToken(ctx context.Context) (*lease.File, error) {
+	t, err := lease.ParseToken(os.Getenv(lease.TokenEnv))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lease.TokenEnv, err)
+	}
+	if leasePool != "" && leasePool != t.Pool {
+		return nil, fmt.Errorf("%w in pool %q (%s holds a lease in pool %q)", lease.ErrNotHeld, leasePool, lease.TokenEnv, t.Pool)
+	}
+
+	lf, err := eng.Lease(ctx, t.Pool, t.LeaseID)
+	if err != nil {
+		return nil, fmt.Errorf("lease in %s: %w", lease.TokenEnv, err)
+	}
+	if lf.SlotName != t.SlotName {
+		return nil, fmt.Errorf("lease in %s is for slot %q, but the lock store has it on %q", lease.TokenEnv, t.SlotName, lf.SlotName)
+	}
+
+	return &lease.File{Version: lease.Version, Leases: []*lease.LeaseFile{lf}}, nil
+}
+
+// saveLeaseFile writes back a lease file from readLeaseFile. Leases from
+// CLAIMENV_LEASE aren't written anywhere.
+func saveLeaseFile(f *lease.File) error {
+	if os.Getenv(lease.TokenEnv) != "" {
+		return nil
+	}
+	return lease.WriteFile(eng.LeaseFile, f)
+}
+
+// selectedLeases returns the lease in --pool, or every lease if it isn't set.
+// The result is a copy, so callers can update f while ranging over it.
+func selectedLeases(f *lease.File) []*lease.LeaseFile {
+	if leasePool == "" {
+		return append([]*lease.LeaseFile(nil), f.Leases...)
+	}
+	lf, _ := f.Get(leasePool)
+	return []*lease.LeaseFile{lf}
+}
+
+// leaseForKey returns the lease in --pool, or else the only held lease whose
+// pool has the key.
+func leaseForKey(ctx context.Context, key string) (*lease.LeaseFile, error) {
+	f, err := readLeaseFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if leasePool != "" || len(f.Leases) == 1 {
+		return f.Get(leasePool)
+	}
+
+	var found []*lease.LeaseFile
+	for _, lf := range f.Leases {
+		if _, ok := lf.Secrets[key]; ok {
+			found = append(found, lf)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("key %q is not defined in any held pool (%s)", key, joinPools(f.Leases))
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("key %q is defined in more than one held pool (%s); choose one with --pool", key, joinPools(found))
+	}
+}
+
+// checkNoLease fails if the lease file already holds a lease in the pool other
+// than leaseID.
+func checkNoLease(f *lease.File, pool, leaseID string) error {
+	existing, err := f.Get(pool)
+	if errors.Is(err, lease.ErrNotHeld) || (err == nil && existing.LeaseID == leaseID) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("already holding slot %q in pool %q. Release it first with: claimenv release --pool %s",
+		existing.SlotName, existing.Pool, existing.Pool)
+}
+
+func joinPools(leases []*lease.LeaseFile) string {
+	pools := make([]string, len(leases))
+	for i, lf := range leases {
+		pools[i] = lf.Pool
+	}
+	return strings.Join(pools, ", ")
+}
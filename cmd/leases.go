@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	leasesMine bool
+	leasesJSON bool
+)
+
+var leasesCmd = &cobra.Command{
+	Use:   "leases",
+	Short: "List active claims across all pools",
+	Long: `Lists the active claims in every pool in the config. With --mine, lists only
+the claims held by this holder's identity, which doesn't need the lease file:
+use it to find what a job still holds after its lease file was lost.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var claims []*lockstore.Claim
+		if leasesMine {
+			mine, err := eng.Leases(cmd.Context())
+			if err != nil {
+				return err
+			}
+			claims = mine
+		} else {
+			for _, poolName := range eng.Cfg.PoolNames() {
+				statuses, err := eng.Status(cmd.Context(), poolName)
+				if err != nil {
+					return err
+				}
+				for _, s := range statuses {
+					if s.Claimed && s.Claim != nil {
+						claims = append(claims, s.Claim)
+					}
+				}
+			}
+		}
+
+		if leasesJSON {
+			out := make([]*api.Claim, len(claims))
+			for i, c := range claims {
+				out[i] = api.PublicClaim(c)
+			}
+			data, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(claims) == 0 {
+			if leasesMine {
+				fmt.Fprintf(os.Stderr, "No active claims held by %s\n", eng.Identity)
+			} else {
+				fmt.Fprintln(os.Stderr, "No active claims")
+			}
+			return nil
+		}
+
+		return printLeasesTable(claims)
+	},
+}
+
+func printLeasesTable(claims []*lockstore.Claim) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POOL\tSLOT\tHOLDER\tCLAIMED\tEXPIRES\tANNOTATIONS")
+
+	for _, c := range claims {
+		annotations := "-"
+		if len(c.Annotations) > 0 {
+			annotations = formatAnnotations(c.Annotations)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Pool, c.SlotName, c.Holder,
+			c.ClaimedAt.Format("2006-01-02 15:04:05"), c.ExpiresAt.Format("2006-01-02 15:04:05"), annotations)
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	leasesCmd.Flags().BoolVar(&leasesMine, "mine", false, "only list claims held by this holder")
+	leasesCmd.Flags().BoolVar(&leasesJSON, "json", false, "output as JSON")
+	rootCmd.AddCommand(leasesCmd)
+}
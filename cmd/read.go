@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/Kashuab/claimenv/internal/lease"
 	"github.com/spf13/cobra"
 )
 
@@ -12,12 +11,14 @@ var readFormat string
 var readCmd = &cobra.Command{
 	Use:   "read <KEY>",
 	Short: "Read a single env var from the claimed slot",
-	Long:  `Reads a value or secret name. Use --format=name to get the GCP Secret Manager secret name instead of the value.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Reads a value or secret name. Use --format=name to get the GCP Secret Manager secret name instead of the value.
+When holding leases in several pools, the key is read from the pool that defines
+it; use --pool if more than one does.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
 
-		lf, err := lease.Load(eng.LeaseFile)
+		lf, err := leaseForKey(cmd.Context(), key)
 		if err != nil {
 			return err
 		}
@@ -43,5 +44,6 @@ var readCmd = &cobra.Command{
 
 func init() {
 	readCmd.Flags().StringVar(&readFormat, "format", "value", "output format: value, name")
+	addPoolFlag(readCmd, "read from the lease in this pool")
 	rootCmd.AddCommand(readCmd)
 }
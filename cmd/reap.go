@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var reapCmd = &cobra.Command{
+	Use:   "reap [pool]",
+	Short: "Clear expired leases",
+	Long: `Scans for expired leases and clears them, reporting which holders let their
+leases lapse. With no arguments, every pool in the config is reaped.
+
+Pools with an expiry_warning also get a lease_expiring notification for every
+lease about to expire, so run reap on a schedule.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pools := args
+		if len(pools) == 0 {
+			for name := range eng.Cfg.Pools {
+				pools = append(pools, name)
+			}
+			sort.Strings(pools)
+		}
+
+		total := 0
+		for _, poolName := range pools {
+			// Reap reports the slots it cleared even when a release hook fails
+			reaped, err := eng.Reap(cmd.Context(), poolName)
+
+			for _, c := range reaped {
+				fmt.Fprintf(os.Stderr, "Reaped slot %q in pool %q (holder %s let the lease lapse at %s)\n",
+					c.SlotName, c.Pool, c.Holder, c.ExpiresAt.Format("2006-01-02 15:04:05"))
+			}
+			total += len(reaped)
+
+			if err != nil {
+				return err
+			}
+
+			if _, err := eng.WarnExpiring(cmd.Context(), poolName); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Reaped %d expired lease(s)\n", total)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reapCmd)
+}
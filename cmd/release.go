@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/Kashuab/claimenv/internal/lease"
+	"github.com/Kashuab/claimenv/pkg/lease"
 	"github.com/spf13/cobra"
 )
 
 var releaseCmd = &cobra.Command{
 	Use:   "release [pool]",
 	Short: "Release the current claim",
-	Long: `Release the current claim. With no arguments, releases using the local lease file.
+	Long: `Release the current claim. With no arguments, releases every lease in the local
+lease file, or only the one in --pool.
 With a pool name argument, releases by holder identity (no lease file needed).`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -22,28 +23,41 @@ With a pool name argument, releases by holder identity (no lease file needed).`,
 				return err
 			}
 			fmt.Fprintf(os.Stderr, "Released claim in pool %q (holder: %s)\n", poolName, eng.Identity)
-			return nil
+
+			// Drop the released lease from the lease file, if it's there
+			f, err := lease.ReadFile(eng.LeaseFile)
+			if err != nil {
+				return err
+			}
+			f.Remove(poolName)
+			return lease.WriteFile(eng.LeaseFile, f)
 		}
 
 		// Release by lease file
-		lf, err := lease.Load(eng.LeaseFile)
+		f, err := readLeaseFile(cmd.Context())
 		if err != nil {
 			return err
 		}
 
-		if err := eng.Release(cmd.Context(), lf); err != nil {
-			return err
-		}
+		for _, lf := range selectedLeases(f) {
+			if err := eng.Release(cmd.Context(), lf); err != nil {
+				// Keep the leases that weren't released
+				if saveErr := saveLeaseFile(f); saveErr != nil {
+					return fmt.Errorf("%w (saving the lease file also failed: %v)", err, saveErr)
+				}
+				return err
+			}
 
-		if err := lease.Delete(eng.LeaseFile); err != nil {
-			return err
+			f.Remove(lf.Pool)
+			fmt.Fprintf(os.Stderr, "Released slot %q from pool %q\n", lf.SlotName, lf.Pool)
 		}
 
-		fmt.Fprintf(os.Stderr, "Released slot %q from pool %q\n", lf.SlotName, lf.Pool)
-		return nil
+		// Removes the file once no leases are left
+		return saveLeaseFile(f)
 	},
 }
 
 func init() {
+	addPoolFlag(releaseCmd, "only release the lease in this pool")
 	rootCmd.AddCommand(releaseCmd)
 }
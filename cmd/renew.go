@@ -1,38 +1,49 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
-	"github.com/Kashuab/claimenv/internal/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
 	"github.com/spf13/cobra"
 )
 
 var renewCmd = &cobra.Command{
 	Use:   "renew",
 	Short: "Extend the TTL on the current claim",
+	Long:  `Extends the TTL of every lease in the lease file, or only the one in --pool.`,
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lf, err := lease.Load(eng.LeaseFile)
+		f, err := readLeaseFile(cmd.Context())
 		if err != nil {
 			return err
 		}
 
-		renewed, err := eng.Renew(cmd.Context(), lf)
-		if err != nil {
-			return err
-		}
+		for _, lf := range selectedLeases(f) {
+			renewed, err := eng.Renew(cmd.Context(), lf)
+			if errors.Is(err, lockstore.ErrMaxLeaseExceeded) {
+				err = fmt.Errorf("lease for slot %q in pool %q reached the pool's max_lease (claimed at %s, expires at %s); release it and claim again",
+					lf.SlotName, lf.Pool, lf.ClaimedAt.Format("2006-01-02 15:04:05"), lf.ExpiresAt.Format("2006-01-02 15:04:05"))
+			}
+			if err != nil {
+				// Keep the expiries of the leases already renewed
+				if saveErr := saveLeaseFile(f); saveErr != nil {
+					return fmt.Errorf("%w (saving the lease file also failed: %v)", err, saveErr)
+				}
+				return err
+			}
 
-		if err := lease.Save(eng.LeaseFile, renewed); err != nil {
-			return err
+			f.Put(renewed)
+			fmt.Fprintf(os.Stderr, "Renewed lease for slot %q in pool %q (new expiry: %s)\n",
+				renewed.SlotName, renewed.Pool, renewed.ExpiresAt.Format("2006-01-02 15:04:05"))
 		}
 
-		fmt.Fprintf(os.Stderr, "Renewed lease for slot %q in pool %q (new expiry: %s)\n",
-			renewed.SlotName, renewed.Pool, renewed.ExpiresAt.Format("2006-01-02 15:04:05"))
-		return nil
+		return saveLeaseFile(f)
 	},
 }
 
 func init() {
+	addPoolFlag(renewCmd, "only renew the lease in this pool")
 	rootCmd.AddCommand(renewCmd)
 }
@@ -1,24 +1,29 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/Kashuab/claimenv/internal/backend"
 	"github.com/Kashuab/claimenv/internal/config"
 	"github.com/Kashuab/claimenv/internal/engine"
 	"github.com/Kashuab/claimenv/internal/identity"
-	"github.com/Kashuab/claimenv/internal/lockstore"
-	lockmem "github.com/Kashuab/claimenv/internal/lockstore/memory"
-	"github.com/Kashuab/claimenv/internal/secretstore"
-	secretmem "github.com/Kashuab/claimenv/internal/secretstore/memory"
+	"github.com/Kashuab/claimenv/internal/tracing"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
 	cfgFile   string
 	leaseFile string
 	eng       *engine.Engine
+
+	// cmdSpan covers the whole command run; stopTracing flushes it.
+	cmdSpan     trace.Span
+	stopTracing func(context.Context) error
 )
 
 var rootCmd = &cobra.Command{
@@ -33,59 +38,37 @@ branch preview deployments, ensuring no two environments share the same credenti
 			return nil
 		}
 
-		v := viper.New()
-
-		if cfgFile != "" {
-			v.SetConfigFile(cfgFile)
-		} else if envCfg := os.Getenv("CLAIMENV_CONFIG"); envCfg != "" {
-			v.SetConfigFile(envCfg)
-		} else {
-			v.SetConfigName("claimenv")
-			v.SetConfigType("yaml")
-			v.AddConfigPath(".")
-			home, _ := os.UserHomeDir()
-			if home != "" {
-				v.AddConfigPath(home + "/.config/claimenv")
-			}
-		}
-
-		if err := v.ReadInConfig(); err != nil {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-
-		cfg, err := config.Load(v)
+		stop, err := tracing.Setup(cmd.Context())
 		if err != nil {
 			return err
 		}
+		stopTracing = stop
+
+		// Join the CI job's trace if it passed one in TRACEPARENT
+		ctx, span := otel.Tracer("github.com/Kashuab/claimenv/cmd").Start(tracing.ContextFromEnv(cmd.Context()), "claimenv "+cmd.Name())
+		cmdSpan = span
+		cmd.SetContext(ctx)
 
-		ls, err := newLockStore(cfg.Backend.Lock)
+		cfg, err := config.ReadFile(cfgFile)
 		if err != nil {
-			return fmt.Errorf("failed to create lock store: %w", err)
+			return err
 		}
 
-		ss, err := newSecretStore(cfg.Backend.Secrets)
+		eng, err = backend.NewEngine(cfg, identity.Resolve(), nil, nil)
 		if err != nil {
-			return fmt.Errorf("failed to create secret store: %w", err)
+			return err
 		}
 
 		// Resolve lease file path
-		lf := leaseFile
-		if lf == "" {
+		eng.LeaseFile = leaseFile
+		if eng.LeaseFile == "" {
 			if envLF := os.Getenv("CLAIMENV_LEASE_FILE"); envLF != "" {
-				lf = envLF
+				eng.LeaseFile = envLF
 			} else {
-				lf = ".claimenv"
+				eng.LeaseFile = ".claimenv"
 			}
 		}
 
-		eng = &engine.Engine{
-			Cfg:         cfg,
-			LockStore:   ls,
-			SecretStore: ss,
-			Identity:    identity.Resolve(),
-			LeaseFile:   lf,
-		}
-
 		return nil
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -97,7 +80,20 @@ branch preview deployments, ensuring no two environments share the same credenti
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	if cmdSpan != nil {
+		tracing.End(cmdSpan, &err)
+	}
+	if stopTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownErr := stopTracing(ctx); shutdownErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to flush traces: %v\n", shutdownErr)
+		}
+	}
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -106,25 +102,3 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file path (default: ./claimenv.yaml)")
 	rootCmd.PersistentFlags().StringVar(&leaseFile, "lease-file", "", "lease file path (default: .claimenv)")
 }
-
-func newLockStore(cfg config.LockBackendConfig) (lockstore.LockStore, error) {
-	switch cfg.Type {
-	case "memory":
-		return lockmem.New(), nil
-	case "firestore":
-		return newFirestoreLockStore(cfg)
-	default:
-		return nil, fmt.Errorf("unknown lock backend type: %q", cfg.Type)
-	}
-}
-
-func newSecretStore(cfg config.SecretBackendConfig) (secretstore.SecretStore, error) {
-	switch cfg.Type {
-	case "memory":
-		return secretmem.New(), nil
-	case "gcp-secret-manager":
-		return newGCPSecretStore(cfg)
-	default:
-		return nil, fmt.Errorf("unknown secret backend type: %q", cfg.Type)
-	}
-}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/server"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	serveListen     string
+	serveTLSCert    string
+	serveTLSKey     string
+	serveGRPC       bool
+	serveGRPCListen string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the claimenv API over HTTP and gRPC",
+	Long: `Exposes claim, renew, release, status, read and write over an authenticated
+HTTP/JSON API, so CI runners can use the http backends instead of holding
+Firestore and Secret Manager credentials. Callers authenticate with one of the
+bearer tokens listed under server.tokens. Runs until interrupted.
+
+With --grpc, also serves the claimenv.v1.LockService gRPC API (see
+proto/claimenv/v1/claimenv.proto), including WatchPool streams of slot changes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tokens := make(map[string]server.Token)
+		for _, t := range eng.Cfg.Server.Tokens {
+			token := os.Getenv(t.Env)
+			if token == "" {
+				return fmt.Errorf("token %q: environment variable %s is not set", t.Name, t.Env)
+			}
+			tokens[token] = server.Token{Name: t.Name, HolderPrefix: t.HolderPrefix, Admin: t.Admin}
+		}
+		if len(tokens) == 0 {
+			return fmt.Errorf("no server.tokens configured; refusing to serve without authentication")
+		}
+
+		if (serveTLSCert == "") != (serveTLSKey == "") {
+			return fmt.Errorf("--tls-cert and --tls-key must be set together")
+		}
+
+		listen := serveListen
+		if listen == "" {
+			listen = eng.Cfg.Server.Listen
+		}
+		if listen == "" {
+			listen = ":8080"
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		api := server.New(eng, tokens)
+		srv := &http.Server{Addr: listen, Handler: api}
+
+		var grpcSrv *grpc.Server
+		var grpcLis net.Listener
+		if serveGRPC {
+			var opts []grpc.ServerOption
+			if serveTLSCert != "" {
+				creds, err := credentials.NewServerTLSFromFile(serveTLSCert, serveTLSKey)
+				if err != nil {
+					return fmt.Errorf("failed to load TLS certificate: %w", err)
+				}
+				opts = append(opts, grpc.Creds(creds))
+			}
+
+			grpcListen := serveGRPCListen
+			if grpcListen == "" {
+				grpcListen = eng.Cfg.Server.GRPCListen
+			}
+			if grpcListen == "" {
+				grpcListen = ":8081"
+			}
+
+			var err error
+			grpcLis, err = net.Listen("tcp", grpcListen)
+			if err != nil {
+				return err
+			}
+			grpcSrv = api.GRPC(opts...)
+		}
+
+		errCh := make(chan error, 2)
+		go func() {
+			if serveTLSCert != "" {
+				errCh <- srv.ListenAndServeTLS(serveTLSCert, serveTLSKey)
+				return
+			}
+			errCh <- srv.ListenAndServe()
+		}()
+		fmt.Fprintf(os.Stderr, "Serving claimenv API on %s\n", listen)
+
+		if grpcSrv != nil {
+			go func() { errCh <- grpcSrv.Serve(grpcLis) }()
+			fmt.Fprintf(os.Stderr, "Serving claimenv gRPC API on %s\n", grpcLis.Addr())
+		}
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if grpcSrv != nil {
+			// WatchPool streams only end when clients hang up, so cut them
+			// off once the grace period is over
+			stopped := make(chan struct{})
+			go func() {
+				grpcSrv.GracefulStop()
+				close(stopped)
+			}()
+			defer func() {
+				select {
+				case <-stopped:
+				case <-shutdownCtx.Done():
+					grpcSrv.Stop()
+				}
+			}()
+		}
+
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "address to listen on (default: server.listen or :8080)")
+	serveCmd.Flags().StringVar(&serveTLSCert, "tls-cert", "", "TLS certificate file; serve plain HTTP if unset")
+	serveCmd.Flags().StringVar(&serveTLSKey, "tls-key", "", "TLS private key file")
+	serveCmd.Flags().BoolVar(&serveGRPC, "grpc", false, "also serve the gRPC API")
+	serveCmd.Flags().StringVar(&serveGRPCListen, "grpc-listen", "", "address for the gRPC API (default: server.grpc_listen or :8081)")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/spf13/cobra"
+)
+
+var slotReason string
+
+var slotCmd = &cobra.Command{
+	Use:   "slot",
+	Short: "Take slots in and out of rotation",
+	Long: `Change the administrative state of a slot without editing the config.
+A draining slot finishes its current lease but accepts no new claims.
+A quarantined slot is never claimable until it is restored, and its active
+claim is revoked immediately.`,
+}
+
+var slotDrainCmd = &cobra.Command{
+	Use:   "drain <pool> <slot>",
+	Short: "Stop handing out a slot once its current lease ends",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSlotState(cmd, args[0], args[1], lockstore.SlotDraining, "")
+	},
+}
+
+var slotQuarantineCmd = &cobra.Command{
+	Use:   "quarantine <pool> <slot>",
+	Short: "Pull a slot out of rotation immediately, revoking its claim",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if slotReason == "" {
+			return fmt.Errorf("--reason is required when quarantining a slot")
+		}
+		return setSlotState(cmd, args[0], args[1], lockstore.SlotQuarantined, slotReason)
+	},
+}
+
+var slotRestoreCmd = &cobra.Command{
+	Use:   "restore <pool> <slot>",
+	Short: "Return a drained or quarantined slot to rotation",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSlotState(cmd, args[0], args[1], lockstore.SlotActive, "")
+	},
+}
+
+var slotRevokeCmd = &cobra.Command{
+	Use:   "revoke <pool> <slot>",
+	Short: "Forcibly release the active claim on a slot",
+	Long: `Releases the slot's active claim regardless of who holds it. Release hooks and
+resettable keys are handled as for a regular release, and the revocation is
+recorded in the slot's history.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		poolName, slotName := args[0], args[1]
+
+		claim, err := eng.Revoke(cmd.Context(), poolName, slotName)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Revoked claim on slot %q in pool %q (holder: %s)\n",
+			slotName, poolName, claim.Holder)
+		return nil
+	},
+}
+
+func setSlotState(cmd *cobra.Command, poolName, slotName string, state lockstore.SlotState, reason string) error {
+	revoked, err := eng.SetSlotState(cmd.Context(), poolName, slotName, state, reason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Slot %q in pool %q is now %s\n", slotName, poolName, state)
+	if revoked != nil {
+		fmt.Fprintf(os.Stderr, "Revoked the active claim held by %s\n", revoked.Holder)
+	}
+	return nil
+}
+
+func init() {
+	slotQuarantineCmd.Flags().StringVar(&slotReason, "reason", "", "why the slot is being quarantined")
+	slotCmd.AddCommand(slotDrainCmd, slotQuarantineCmd, slotRestoreCmd, slotRevokeCmd)
+	rootCmd.AddCommand(slotCmd)
+}
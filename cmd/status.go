@@ -1,24 +1,56 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
-	"github.com/Kashuab/claimenv/internal/lockstore"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
 	"github.com/spf13/cobra"
 )
 
-var statusJSON bool
+var (
+	statusJSON     bool
+	statusWatch    bool
+	statusInterval time.Duration
+)
 
 var statusCmd = &cobra.Command{
-	Use:   "status <pool>",
+	Use:   "status [pool]",
 	Short: "Show the status of all slots in a pool",
-	Args:  cobra.ExactArgs(1),
+	Long: `Shows the status of all slots in a pool, or in every pool in the config if no
+pool is given.
+
+With --watch, keeps the table on screen and redraws it whenever a slot changes,
+until interrupted. The Firestore backend pushes changes as they happen; other
+backends are polled every --interval. With --json, each update is printed as
+one line of JSON instead.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if statusWatch {
+				return fmt.Errorf("--watch needs a pool name")
+			}
+			return statusAllPools(cmd.Context())
+		}
 		poolName := args[0]
 
+		if statusWatch {
+			if statusInterval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			return watchStatus(cmd.Context(), poolName)
+		}
+
 		statuses, err := eng.Status(cmd.Context(), poolName)
 		if err != nil {
 			return err
@@ -28,10 +60,80 @@ var statusCmd = &cobra.Command{
 			return printStatusJSON(statuses)
 		}
 
-		return printStatusTable(statuses)
+		return printStatusTable(os.Stdout, statuses)
 	},
 }
 
+// statusAllPools prints the status of every pool in the config. With --json,
+// the statuses are printed as one object keyed by pool name.
+func statusAllPools(ctx context.Context) error {
+	byPool := make(map[string][]lockstore.SlotStatus)
+	for _, poolName := range eng.Cfg.PoolNames() {
+		statuses, err := eng.Status(ctx, poolName)
+		if err != nil {
+			return err
+		}
+		byPool[poolName] = statuses
+	}
+
+	if statusJSON {
+		data, err := json.MarshalIndent(byPool, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for i, poolName := range eng.Cfg.PoolNames() {
+		if i > 0 {
+			fmt.Println()
+		}
+		claimed := 0
+		for _, s := range byPool[poolName] {
+			if s.Claimed {
+				claimed++
+			}
+		}
+		fmt.Printf("Pool %s (%d/%d claimed)\n", poolName, claimed, len(byPool[poolName]))
+		if err := printStatusTable(os.Stdout, byPool[poolName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchStatus redraws the pool's status whenever it changes, until interrupted.
+func watchStatus(ctx context.Context, poolName string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var last []byte
+	return eng.Watch(ctx, poolName, statusInterval, func(statuses []lockstore.SlotStatus) error {
+		if statusJSON {
+			data, err := json.Marshal(statuses)
+			if err != nil {
+				return err
+			}
+			// Polled backends report unchanged pools too
+			if !bytes.Equal(data, last) {
+				fmt.Println(string(data))
+				last = data
+			}
+			return nil
+		}
+
+		// Render off-screen first so the redraw doesn't flicker
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "Pool %s, updated %s (Ctrl-C to exit)\n\n", poolName, time.Now().Format("15:04:05"))
+		if err := printStatusTable(&buf, statuses); err != nil {
+			return err
+		}
+		_, err := os.Stdout.Write(append([]byte("\033[H\033[2J"), buf.Bytes()...))
+		return err
+	})
+}
+
 func printStatusJSON(statuses []lockstore.SlotStatus) error {
 	data, err := json.MarshalIndent(statuses, "", "  ")
 	if err != nil {
@@ -41,28 +143,66 @@ func printStatusJSON(statuses []lockstore.SlotStatus) error {
 	return nil
 }
 
-func printStatusTable(statuses []lockstore.SlotStatus) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "SLOT\tSTATUS\tHOLDER\tEXPIRES")
+func printStatusTable(out io.Writer, statuses []lockstore.SlotStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tSTATUS\tHOLDER\tEXPIRES\tNEXT RESERVATION\tANNOTATIONS")
 
 	for _, s := range statuses {
-		status := "free"
+		status := s.Phase()
 		holder := "-"
 		expires := "-"
 
-		if s.Claimed && s.Claim != nil {
-			status = "claimed"
+		if (s.Claimed || s.Expired) && s.Claim != nil {
 			holder = s.Claim.Holder
 			expires = s.Claim.ExpiresAt.Format("2006-01-02 15:04:05")
+		} else if s.CoolingUntil != nil {
+			expires = s.CoolingUntil.Format("2006-01-02 15:04:05")
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.SlotName, status, holder, expires)
+		if s.State == lockstore.SlotQuarantined && s.Reason != "" {
+			status = fmt.Sprintf("quarantined (%s)", s.Reason)
+		}
+
+		reserved := "-"
+		if len(s.Reservations) > 0 {
+			next := s.Reservations[0]
+			for _, r := range s.Reservations[1:] {
+				if r.From.Before(next.From) {
+					next = r
+				}
+			}
+			reserved = fmt.Sprintf("%s at %s", next.Holder, next.From.Format("2006-01-02 15:04:05"))
+		}
+
+		annotations := "-"
+		if s.Claim != nil && len(s.Claim.Annotations) > 0 {
+			annotations = formatAnnotations(s.Claim.Annotations)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.SlotName, status, holder, expires, reserved, annotations)
 	}
 
 	return w.Flush()
 }
 
+// formatAnnotations renders annotations as sorted key=value pairs.
+func formatAnnotations(annotations map[string]string) string {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + annotations[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
 func init() {
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "output as JSON")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "redraw the status whenever it changes")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "how often to poll backends that can't push changes")
 	rootCmd.AddCommand(statusCmd)
 }
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiInterval time.Duration
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Administer every pool from a full-screen terminal UI",
+	Long: `Lists every pool in the config with its slots, holders, annotations and time
+left on each lease, updated live. Select a slot to renew, release or revoke its
+claim, quarantine or restore it, or view its history. Destructive actions ask
+for confirmation first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tuiInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		return tui.Run(cmd.Context(), eng, tuiInterval)
+	},
+}
+
+func init() {
+	tuiCmd.Flags().DurationVar(&tuiInterval, "interval", 5*time.Second, "how often to poll backends that can't push changes")
+	rootCmd.AddCommand(tuiCmd)
+}
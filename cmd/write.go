@@ -4,18 +4,20 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/Kashuab/claimenv/internal/lease"
 	"github.com/spf13/cobra"
 )
 
 var writeCmd = &cobra.Command{
 	Use:   "write <KEY> <VALUE>",
 	Short: "Write a single env var to the claimed slot",
-	Args:  cobra.ExactArgs(2),
+	Long: `Writes a single env var to the claimed slot. When holding leases in several
+pools, the key is written to the pool that defines it; use --pool if more than
+one does.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key, value := args[0], args[1]
 
-		lf, err := lease.Load(eng.LeaseFile)
+		lf, err := leaseForKey(cmd.Context(), key)
 		if err != nil {
 			return err
 		}
@@ -30,5 +32,6 @@ var writeCmd = &cobra.Command{
 }
 
 func init() {
+	addPoolFlag(writeCmd, "write to the lease in this pool")
 	rootCmd.AddCommand(writeCmd)
 }
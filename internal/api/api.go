@@ -0,0 +1,221 @@
+// Package api defines the HTTP/JSON protocol spoken between "claimenv serve"
+// and the http lock and secret backends: request bodies, headers, and how
+// sentinel errors travel over the wire.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+)
+
+const (
+	// LeaseHeader carries the lease ID for lease-scoped requests. It's kept
+	// out of URLs so it doesn't end up in proxy access logs.
+	LeaseHeader = "Claimenv-Lease"
+
+	// IdentityHeader carries the caller's identity, recorded in audit events.
+	IdentityHeader = "Claimenv-Identity"
+)
+
+// ClaimRequest is the body of POST /v1/pools/{pool}/claims.
+type ClaimRequest struct {
+	Holder      string            `json:"holder"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SlotStatus is a slot's status as served by GET /v1/pools/{pool}/status,
+// with a claim that has no lease ID.
+type SlotStatus struct {
+	lockstore.SlotStatus
+	Claim *Claim `json:"claim,omitempty"`
+}
+
+// Claim is a claim without its lease ID.
+type Claim struct {
+	Pool        string            `json:"pool"`
+	SlotName    string            `json:"slot_name"`
+	Holder      string            `json:"holder"`
+	ClaimedAt   time.Time         `json:"claimed_at"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PublicClaim returns c with the lease ID left out.
+func PublicClaim(c *lockstore.Claim) *Claim {
+	return &Claim{
+		Pool:        c.Pool,
+		SlotName:    c.SlotName,
+		Holder:      c.Holder,
+		ClaimedAt:   c.ClaimedAt,
+		ExpiresAt:   c.ExpiresAt,
+		Annotations: c.Annotations,
+	}
+}
+
+// PublicStatuses returns statuses with the lease IDs left out.
+func PublicStatuses(statuses []lockstore.SlotStatus) []SlotStatus {
+	out := make([]SlotStatus, len(statuses))
+	for i, st := range statuses {
+		out[i] = SlotStatus{SlotStatus: st}
+		if st.Claim != nil {
+			out[i].Claim = PublicClaim(st.Claim)
+		}
+	}
+	return out
+}
+
+// Value is the body of a single secret read or write.
+type Value struct {
+	Value string `json:"value"`
+}
+
+// Error is the body of every non-2xx response.
+type Error struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// errorCodes maps the sentinel errors callers test for with errors.Is to the
+// codes and HTTP statuses used on the wire.
+var errorCodes = []struct {
+	err    error
+	code   string
+	status int
+}{
+	{lockstore.ErrPoolExhausted, "pool_exhausted", http.StatusConflict},
+	{lockstore.ErrLeaseNotFound, "lease_not_found", http.StatusNotFound},
+	{lockstore.ErrLeaseExpired, "lease_expired", http.StatusGone},
+	{lockstore.ErrMaxLeaseExceeded, "max_lease_exceeded", http.StatusConflict},
+	{lockstore.ErrQuotaExceeded, "quota_exceeded", http.StatusConflict},
+	{lockstore.ErrSlotReserved, "slot_reserved", http.StatusConflict},
+	{lockstore.ErrReservationConflict, "reservation_conflict", http.StatusConflict},
+	{lockstore.ErrReservationNotFound, "reservation_not_found", http.StatusNotFound},
+	{secretstore.ErrSecretNotFound, "secret_not_found", http.StatusNotFound},
+}
+
+// StatusFor returns the HTTP status and error code for err. Errors without a
+// code are reported as 500s.
+func StatusFor(err error) (int, string) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.status, c.code
+		}
+	}
+	return http.StatusInternalServerError, ""
+}
+
+// remoteError is an error returned by the server. It keeps the server's
+// message and unwraps to the sentinel error named by its code, if any.
+type remoteError struct {
+	msg      string
+	sentinel error
+}
+
+func (e *remoteError) Error() string { return e.msg }
+func (e *remoteError) Unwrap() error { return e.sentinel }
+
+// ErrorFor rebuilds the error described by a response body.
+func ErrorFor(body Error) error {
+	e := &remoteError{msg: body.Error}
+	for _, c := range errorCodes {
+		if c.code == body.Code {
+			e.sentinel = c.err
+		}
+	}
+	return e
+}
+
+// Client sends requests to a claimenv server.
+type Client struct {
+	// URL is the server's base URL, e.g. "https://claimenv.example.com".
+	URL string
+
+	// Token is sent as a bearer token with every request.
+	Token string
+
+	// Identity is sent in the IdentityHeader of every request.
+	Identity string
+
+	// HTTP defaults to an http.Client with a 30 second timeout.
+	HTTP *http.Client
+}
+
+// PoolPath returns the escaped path of a pool resource, e.g.
+// PoolPath("onboard", "lease", "env") is "/v1/pools/onboard/lease/env".
+func PoolPath(pool string, elems ...string) string {
+	parts := []string{"v1", "pools", url.PathEscape(pool)}
+	for _, e := range elems {
+		parts = append(parts, url.PathEscape(e))
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// Do sends a request with in as its JSON body (if not nil) and decodes the
+// JSON response into out (if not nil). leaseID, if set, goes in the
+// LeaseHeader. Error responses are returned as errors that unwrap to the
+// matching sentinel error.
+func (c *Client) Do(ctx context.Context, method, path, leaseID string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.URL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Identity != "" {
+		req.Header.Set(IdentityHeader, c.Identity)
+	}
+	if leaseID != "" {
+		req.Header.Set(LeaseHeader, leaseID)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("claimenv server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e Error
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			return fmt.Errorf("claimenv server returned %s", resp.Status)
+		}
+		return ErrorFor(e)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode claimenv server response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Action is the kind of secret access being audited.
+type Action string
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
+// Event records a single secret access. It never contains the secret value.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Action   Action    `json:"action"`
+	Pool     string    `json:"pool"`
+	Slot     string    `json:"slot"`
+	Key      string    `json:"key"`
+	LeaseID  string    `json:"lease_id"`
+	Holder   string    `json:"holder"`
+	Identity string    `json:"identity"` // who accessed the secret; may differ from the lease holder
+
+	// Actor names what accessed the secret on the identity's behalf, e.g.
+	// "hook:post_claim" or "reset". It is empty for direct reads and writes.
+	Actor string `json:"actor,omitempty"`
+}
+
+// Sink receives audit events.
+type Sink interface {
+	// Emit records ev. An error means the event was not recorded.
+	Emit(ctx context.Context, ev Event) error
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// Appender is implemented by lock stores that can keep the audit log in the
+// lock backend.
+type Appender interface {
+	AppendAudit(ctx context.Context, ev Event) error
+}
+
+// BackendSink delivers events to the lock backend.
+type BackendSink struct {
+	appender Appender
+}
+
+func NewBackendSink(a Appender) *BackendSink {
+	return &BackendSink{appender: a}
+}
+
+func (s *BackendSink) Emit(ctx context.Context, ev Event) error {
+	return s.appender.AppendAudit(ctx, ev)
+}
+
+// Close is a no-op; the lock store is closed by its owner.
+func (s *BackendSink) Close() error {
+	return nil
+}
@@ -0,0 +1,50 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/audit"
+)
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+
+	// Reopening the sink appends rather than truncating
+	for _, key := range []string{"SHOPIFY_API_KEY", "APP_URL"} {
+		sink, err := audit.NewFileSink(path)
+		if err != nil {
+			t.Fatalf("NewFileSink failed: %v", err)
+		}
+		if err := sink.Emit(ctx, audit.Event{Time: time.Now(), Action: audit.ActionRead, Pool: "onboard", Key: key}); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	var ev audit.Event
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatalf("failed to parse line: %v", err)
+	}
+	if ev.Key != "APP_URL" || ev.Action != audit.ActionRead {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build windows || plan9
+
+package audit
+
+import (
+	"context"
+	"errors"
+)
+
+// SyslogSink is unavailable on this platform.
+type SyslogSink struct{}
+
+func NewSyslogSink(network, address, tag string) (*SyslogSink, error) {
+	return nil, errors.New("syslog audit sink is not supported on this platform")
+}
+
+func (s *SyslogSink) Emit(_ context.Context, _ Event) error {
+	return errors.New("syslog audit sink is not supported on this platform")
+}
+
+func (s *SyslogSink) Close() error {
+	return nil
+}
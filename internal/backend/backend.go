@@ -0,0 +1,143 @@
+// Package backend builds the stores, sinks and engine described by a config,
+// for both the CLI and the public client library.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/internal/audit"
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/internal/notify"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	firestorelock "github.com/Kashuab/claimenv/pkg/lockstore/firestore"
+	lockhttp "github.com/Kashuab/claimenv/pkg/lockstore/httpstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore/gcpsm"
+	secrethttp "github.com/Kashuab/claimenv/pkg/secretstore/httpstore"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+)
+
+// NewEngine returns an engine acting as identity, with the lock store, secret
+// store, audit sink and notifier configured in cfg. A non-nil ls or ss is used
+// instead of the configured store. The caller sets LeaseFile.
+func NewEngine(cfg *config.Config, identity string, ls lockstore.LockStore, ss secretstore.SecretStore) (*engine.Engine, error) {
+	var err error
+	if ls == nil {
+		ls, err = LockStore(cfg.Backend.Lock, identity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lock store: %w", err)
+		}
+	}
+
+	if ss == nil {
+		ss, err = SecretStore(cfg.Backend.Secrets, identity)
+		if err != nil {
+			ls.Close()
+			return nil, fmt.Errorf("failed to create secret store: %w", err)
+		}
+	}
+
+	sink, err := AuditSink(cfg.Audit, ls)
+	if err != nil {
+		ls.Close()
+		ss.Close()
+		return nil, fmt.Errorf("failed to create audit sink: %w", err)
+	}
+
+	return &engine.Engine{
+		Cfg:         cfg,
+		LockStore:   ls,
+		SecretStore: ss,
+		Identity:    identity,
+		Audit:       sink,
+		Notifier:    Notifier(cfg.Notifications),
+	}, nil
+}
+
+// LockStore returns the lock store selected by cfg. identity is sent to the
+// server by the http backend.
+func LockStore(cfg config.LockBackendConfig, identity string) (lockstore.LockStore, error) {
+	switch cfg.Type {
+	case "memory":
+		return lockmem.New(), nil
+	case "firestore":
+		return firestorelock.New(context.Background(), cfg.Project, cfg.Collection)
+	case "http":
+		return lockhttp.New(apiClient(cfg.URL, identity)), nil
+	default:
+		return nil, fmt.Errorf("unknown lock backend type: %q", cfg.Type)
+	}
+}
+
+// SecretStore returns the secret store selected by cfg. identity is sent to
+// the server by the http backend.
+func SecretStore(cfg config.SecretBackendConfig, identity string) (secretstore.SecretStore, error) {
+	switch cfg.Type {
+	case "memory":
+		return secretmem.New(), nil
+	case "gcp-secret-manager":
+		return gcpsm.New(context.Background(), cfg.Project)
+	case "http":
+		return secrethttp.New(apiClient(cfg.URL, identity)), nil
+	default:
+		return nil, fmt.Errorf("unknown secret backend type: %q", cfg.Type)
+	}
+}
+
+// AuditSink returns the audit sink selected by cfg, or nil if auditing is off.
+// The backend sink stores events through ls.
+func AuditSink(cfg config.AuditConfig, ls lockstore.LockStore) (audit.Sink, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "file":
+		return audit.NewFileSink(cfg.Path)
+	case "syslog":
+		tag := cfg.Tag
+		if tag == "" {
+			tag = "claimenv"
+		}
+		return audit.NewSyslogSink(cfg.Network, cfg.Address, tag)
+	case "backend":
+		appender, ok := ls.(audit.Appender)
+		if !ok {
+			return nil, fmt.Errorf("lock backend does not support audit logging")
+		}
+		return audit.NewBackendSink(appender), nil
+	default:
+		return nil, fmt.Errorf("unknown audit type: %q", cfg.Type)
+	}
+}
+
+// Notifier returns a notifier posting to every configured webhook, or nil if
+// there are none.
+func Notifier(cfgs []config.NotificationConfig) notify.Notifier {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	var m notify.Multi
+	for _, c := range cfgs {
+		w := &notify.Webhook{URL: c.URL, Format: c.Format}
+		for _, ev := range c.Events {
+			w.Events = append(w.Events, notify.EventType(ev))
+		}
+		m = append(m, w)
+	}
+	return m
+}
+
+// apiClient returns a client for the claimenv server at url, authenticating
+// with the bearer token in CLAIMENV_TOKEN.
+func apiClient(url, identity string) *api.Client {
+	return &api.Client{
+		URL:      url,
+		Token:    os.Getenv("CLAIMENV_TOKEN"),
+		Identity: identity,
+	}
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +15,48 @@ import (
 type Config struct {
 	Backend BackendConfig         `yaml:"backend" mapstructure:"backend"`
 	Pools   map[string]PoolConfig `yaml:"pools"   mapstructure:"pools"`
+	Audit   AuditConfig           `yaml:"audit"   mapstructure:"audit"`
+
+	Notifications []NotificationConfig `yaml:"notifications" mapstructure:"notifications"`
+
+	Server ServerConfig `yaml:"server" mapstructure:"server"`
+}
+
+// ServerConfig configures "claimenv serve". Token values are read from the
+// named environment variables so they never live in the config file.
+type ServerConfig struct {
+	Listen     string        `yaml:"listen"      mapstructure:"listen"`      // defaults to ":8080"
+	GRPCListen string        `yaml:"grpc_listen" mapstructure:"grpc_listen"` // defaults to ":8081"; used with --grpc
+	Tokens     []TokenConfig `yaml:"tokens"      mapstructure:"tokens"`
+}
+
+// TokenConfig is a bearer token accepted by the server. Name identifies
+// callers using it when they don't send their own identity. Callers may only
+// act as holders starting with HolderPrefix, which defaults to Name. Only
+// admin tokens may revoke, reap, reserve and change slot states over gRPC.
+type TokenConfig struct {
+	Name         string `yaml:"name"          mapstructure:"name"`
+	Env          string `yaml:"env"           mapstructure:"env"`
+	HolderPrefix string `yaml:"holder_prefix" mapstructure:"holder_prefix"`
+	Admin        bool   `yaml:"admin"         mapstructure:"admin"`
+}
+
+// NotificationConfig is a webhook that receives pool events as JSON POSTs.
+// An empty Events list subscribes to every event.
+type NotificationConfig struct {
+	URL    string   `yaml:"url"    mapstructure:"url"`
+	Format string   `yaml:"format" mapstructure:"format"` // "json" (default) or "slack"
+	Events []string `yaml:"events" mapstructure:"events"`
+}
+
+// AuditConfig selects where secret read/write audit events are delivered.
+// An empty Type disables auditing.
+type AuditConfig struct {
+	Type    string `yaml:"type"    mapstructure:"type"`    // "file", "syslog" or "backend"
+	Path    string `yaml:"path"    mapstructure:"path"`    // file only
+	Network string `yaml:"network" mapstructure:"network"` // syslog only; empty for the local daemon
+	Address string `yaml:"address" mapstructure:"address"` // syslog only
+	Tag     string `yaml:"tag"     mapstructure:"tag"`     // syslog only; defaults to "claimenv"
 }
 
 type BackendConfig struct {
@@ -23,17 +68,66 @@ type LockBackendConfig struct {
 	Type       string `yaml:"type"       mapstructure:"type"`
 	Project    string `yaml:"project"    mapstructure:"project"`
 	Collection string `yaml:"collection" mapstructure:"collection"`
+	URL        string `yaml:"url"        mapstructure:"url"` // http only
 }
 
 type SecretBackendConfig struct {
 	Type    string `yaml:"type"    mapstructure:"type"`
 	Project string `yaml:"project" mapstructure:"project"`
+	URL     string `yaml:"url"     mapstructure:"url"` // http only
 }
 
 type PoolConfig struct {
 	Slots []SlotConfig  `yaml:"slots" mapstructure:"slots"`
 	Keys  []string      `yaml:"keys"  mapstructure:"keys"`
 	TTL   time.Duration `yaml:"ttl"   mapstructure:"ttl"`
+	Hooks HooksConfig   `yaml:"hooks" mapstructure:"hooks"`
+
+	// Cooldown keeps a just-released slot out of rotation for a while.
+	Cooldown time.Duration `yaml:"cooldown" mapstructure:"cooldown"`
+
+	// MaxLease caps a claim's lifetime from ClaimedAt, regardless of renewals.
+	MaxLease time.Duration `yaml:"max_lease" mapstructure:"max_lease"`
+
+	// ExpiryWarning sends a lease_expiring notification for leases this close
+	// to expiry when the pool is reaped. Zero disables the warning.
+	ExpiryWarning time.Duration `yaml:"expiry_warning" mapstructure:"expiry_warning"`
+
+	Quotas []QuotaConfig `yaml:"quotas" mapstructure:"quotas"`
+
+	// Resettable keys are restored to their baseline value on release.
+	Resettable []string `yaml:"resettable" mapstructure:"resettable"`
+}
+
+// HooksConfig holds shell commands run by the engine around a slot's lease.
+// Each command runs via "sh -c" with the slot name, holder and secret values in
+// its environment. An empty command is skipped.
+type HooksConfig struct {
+	PostClaim   string `yaml:"post_claim"   mapstructure:"post_claim"`
+	PreRelease  string `yaml:"pre_release"  mapstructure:"pre_release"`
+	PostRelease string `yaml:"post_release" mapstructure:"post_release"`
+}
+
+// QuotaConfig limits concurrent claims per holder group. Match is a regular
+// expression applied to the holder identity; its first capture group (or the
+// whole match) names the group, e.g. "^gitlab-(\d+)-" groups holders by project.
+type QuotaConfig struct {
+	Name  string `yaml:"name"  mapstructure:"name"`
+	Match string `yaml:"match" mapstructure:"match"`
+	Max   int    `yaml:"max"   mapstructure:"max"`
+}
+
+// NotificationEvents lists the event names a notification can subscribe to.
+var NotificationEvents = []string{"pool_exhausted", "lease_expired", "lease_expiring", "slot_quarantined"}
+
+// PoolNames returns the names of all pools in the config, sorted.
+func (c *Config) PoolNames() []string {
+	names := make([]string, 0, len(c.Pools))
+	for name := range c.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 type SlotConfig struct {
@@ -49,6 +143,16 @@ func (p *PoolConfig) SlotNames() []string {
 	return names
 }
 
+// HasSlot reports whether the pool defines a slot with the given name.
+func (p *PoolConfig) HasSlot(slotName string) bool {
+	for _, s := range p.Slots {
+		if s.Name == slotName {
+			return true
+		}
+	}
+	return false
+}
+
 // SecretName derives the GCP Secret Manager secret name for a given slot and key.
 // Convention: {slot-name}-{kebab-case-key}, e.g. "app-alpha" + "SHOPIFY_API_SECRET" → "app-alpha-shopify-api-secret".
 func SecretName(slotName, key string) string {
@@ -56,6 +160,13 @@ func SecretName(slotName, key string) string {
 	return slotName + "-" + kebab
 }
 
+// BaselineSecretName derives the name of the secret holding the baseline value
+// of a resettable key: the key's secret name with a "-baseline" suffix,
+// e.g. "app-alpha" + "APP_URL" → "app-alpha-app-url-baseline".
+func BaselineSecretName(slotName, key string) string {
+	return SecretName(slotName, key) + "-baseline"
+}
+
 // SecretsForSlot returns a map of env var key → derived secret name for the given slot.
 func (p *PoolConfig) SecretsForSlot(slotName string) map[string]string {
 	m := make(map[string]string, len(p.Keys))
@@ -65,6 +176,34 @@ func (p *PoolConfig) SecretsForSlot(slotName string) map[string]string {
 	return m
 }
 
+// ReadFile reads and validates the config at path. If path is empty, it uses
+// CLAIMENV_CONFIG, or else looks for claimenv.yaml in the working directory
+// and then in ~/.config/claimenv.
+func ReadFile(path string) (*Config, error) {
+	v := viper.New()
+
+	if path == "" {
+		path = os.Getenv("CLAIMENV_CONFIG")
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("claimenv")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		home, _ := os.UserHomeDir()
+		if home != "" {
+			v.AddConfigPath(home + "/.config/claimenv")
+		}
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return Load(v)
+}
+
 func Load(v *viper.Viper) (*Config, error) {
 	var cfg Config
 	err := v.Unmarshal(&cfg, viper.DecodeHook(
@@ -91,6 +230,46 @@ func validate(cfg *Config) error {
 	if cfg.Backend.Secrets.Type == "" {
 		return fmt.Errorf("backend.secrets.type is required")
 	}
+	switch cfg.Audit.Type {
+	case "", "syslog", "backend":
+	case "file":
+		if cfg.Audit.Path == "" {
+			return fmt.Errorf("audit.path is required for the file audit sink")
+		}
+	default:
+		return fmt.Errorf("unknown audit type: %q", cfg.Audit.Type)
+	}
+	if cfg.Backend.Lock.Type == "http" {
+		if cfg.Backend.Lock.URL == "" {
+			return fmt.Errorf("backend.lock.url is required for the http lock backend")
+		}
+		if err := validateRunner(cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.Backend.Secrets.Type == "http" && cfg.Backend.Secrets.URL == "" {
+		return fmt.Errorf("backend.secrets.url is required for the http secret backend")
+	}
+	for i, t := range cfg.Server.Tokens {
+		if t.Name == "" || t.Env == "" {
+			return fmt.Errorf("server.tokens %d: name and env are required", i)
+		}
+	}
+	for i, n := range cfg.Notifications {
+		if n.URL == "" {
+			return fmt.Errorf("notifications %d: url is required", i)
+		}
+		switch n.Format {
+		case "", "json", "slack":
+		default:
+			return fmt.Errorf("notifications %d: unknown format: %q", i, n.Format)
+		}
+		for _, ev := range n.Events {
+			if !containsString(NotificationEvents, ev) {
+				return fmt.Errorf("notifications %d: unknown event: %q", i, ev)
+			}
+		}
+	}
 	if len(cfg.Pools) == 0 {
 		return fmt.Errorf("at least one pool must be defined")
 	}
@@ -104,6 +283,28 @@ func validate(cfg *Config) error {
 		if pool.TTL <= 0 {
 			return fmt.Errorf("pool %q: ttl must be > 0", name)
 		}
+		if pool.Cooldown < 0 {
+			return fmt.Errorf("pool %q: cooldown must be >= 0", name)
+		}
+		if pool.ExpiryWarning < 0 {
+			return fmt.Errorf("pool %q: expiry_warning must be >= 0", name)
+		}
+		if pool.MaxLease != 0 && pool.MaxLease < pool.TTL {
+			return fmt.Errorf("pool %q: max_lease must be >= ttl", name)
+		}
+		for i, q := range pool.Quotas {
+			if _, err := regexp.Compile(q.Match); err != nil {
+				return fmt.Errorf("pool %q: quota %d: invalid match pattern: %w", name, i, err)
+			}
+			if q.Max <= 0 {
+				return fmt.Errorf("pool %q: quota %d: max must be > 0", name, i)
+			}
+		}
+		for _, key := range pool.Resettable {
+			if !containsString(pool.Keys, key) {
+				return fmt.Errorf("pool %q: resettable key %q is not in keys", name, key)
+			}
+		}
 		seen := make(map[string]bool)
 		for i, slot := range pool.Slots {
 			if slot.Name == "" {
@@ -117,3 +318,37 @@ func validate(cfg *Config) error {
 	}
 	return nil
 }
+
+// validateRunner rejects the settings a runner using the http lock backend
+// would otherwise apply on top of the server, which already applies its own.
+// A runner's secret store only serves lease-scoped requests, so its hooks and
+// resets couldn't even read the slot.
+func validateRunner(cfg *Config) error {
+	if cfg.Audit.Type != "" {
+		return fmt.Errorf("audit is applied by the server and can't be set with the http lock backend")
+	}
+	if len(cfg.Notifications) > 0 {
+		return fmt.Errorf("notifications are sent by the server and can't be set with the http lock backend")
+	}
+	for name, pool := range cfg.Pools {
+		if pool.Hooks != (HooksConfig{}) {
+			return fmt.Errorf("pool %q: hooks run on the server and can't be set with the http lock backend", name)
+		}
+		if len(pool.Resettable) > 0 {
+			return fmt.Errorf("pool %q: resettable keys are reset by the server and can't be set with the http lock backend", name)
+		}
+		if len(pool.Quotas) > 0 {
+			return fmt.Errorf("pool %q: quotas are enforced by the server and can't be set with the http lock backend", name)
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
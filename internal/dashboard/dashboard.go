@@ -0,0 +1,246 @@
+// Package dashboard serves a read-only web page of every pool's slots, holders
+// and recent utilization, for people who don't have the CLI or backend
+// credentials. It never reads secrets and never shows lease IDs.
+package dashboard
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+)
+
+const (
+	// Window is the span covered by the utilization sparklines.
+	Window = 24 * time.Hour
+
+	// buckets is the number of points in a sparkline.
+	buckets = 48
+
+	sparkWidth  = 144
+	sparkHeight = 24
+)
+
+//go:embed index.html
+var indexHTML string
+
+var indexTmpl = template.Must(template.New("index").Funcs(template.FuncMap{
+	"sparkline": sparkline,
+}).Parse(indexHTML))
+
+// Dashboard is an http.Handler serving the dashboard page on / and the same
+// data as JSON on /api/pools.
+type Dashboard struct {
+	eng *engine.Engine
+
+	// CacheTTL is how long pool data is reused between requests, so a busy
+	// page doesn't hammer the lock store. Defaults to 15 seconds.
+	CacheTTL time.Duration
+
+	mu       sync.Mutex
+	cached   []Pool
+	cachedAt time.Time
+
+	mux *http.ServeMux
+}
+
+// Pool is a pool as shown on the dashboard.
+type Pool struct {
+	Name    string `json:"name"`
+	Slots   []Slot `json:"slots"`
+	Claimed int    `json:"claimed"`
+	Total   int    `json:"total"`
+
+	// Utilization is the fraction of slots claimed over the last Window,
+	// oldest first. It's empty when the lock store keeps no history.
+	Utilization []float64 `json:"utilization"`
+}
+
+// Slot is a slot as shown on the dashboard. It deliberately has no lease ID.
+type Slot struct {
+	Name        string            `json:"name"`
+	Phase       string            `json:"phase"`
+	Reason      string            `json:"reason,omitempty"`
+	Holder      string            `json:"holder,omitempty"`
+	ClaimedAt   *time.Time        `json:"claimed_at,omitempty"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// New returns a dashboard for every pool in eng's config.
+func New(eng *engine.Engine) *Dashboard {
+	d := &Dashboard{eng: eng, mux: http.NewServeMux()}
+	d.mux.HandleFunc("GET /{$}", d.index)
+	d.mux.HandleFunc("GET /api/pools", d.api)
+	return d
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+func (d *Dashboard) index(w http.ResponseWriter, r *http.Request) {
+	pools, updated, err := d.snapshot(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	indexTmpl.Execute(w, struct {
+		Pools   []Pool
+		Updated time.Time
+	}{pools, updated})
+}
+
+func (d *Dashboard) api(w http.ResponseWriter, r *http.Request) {
+	pools, err := d.Pools(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pools)
+}
+
+// Pools returns every pool in the config, sorted by name, reusing the last
+// result for CacheTTL.
+func (d *Dashboard) Pools(ctx context.Context) ([]Pool, error) {
+	pools, _, err := d.snapshot(ctx)
+	return pools, err
+}
+
+// snapshot returns the pools and when they were read.
+func (d *Dashboard) snapshot(ctx context.Context) ([]Pool, time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ttl := d.CacheTTL
+	if ttl <= 0 {
+		ttl = 15 * time.Second
+	}
+	if d.cached != nil && time.Since(d.cachedAt) < ttl {
+		return d.cached, d.cachedAt, nil
+	}
+
+	names := make([]string, 0, len(d.eng.Cfg.Pools))
+	for name := range d.eng.Cfg.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	pools := make([]Pool, 0, len(names))
+	for _, name := range names {
+		p, err := d.pool(ctx, name, now)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("pool %q: %w", name, err)
+		}
+		pools = append(pools, p)
+	}
+
+	d.cached, d.cachedAt = pools, now
+	return pools, now, nil
+}
+
+func (d *Dashboard) pool(ctx context.Context, name string, now time.Time) (Pool, error) {
+	statuses, err := d.eng.Status(ctx, name)
+	if err != nil {
+		return Pool{}, err
+	}
+	// The http lock backend keeps history on the server; show the pool
+	// without a sparkline rather than failing the page
+	events, err := d.eng.History(ctx, name, "", now.Add(-Window))
+	hasHistory := !errors.Is(err, errors.ErrUnsupported)
+	if err != nil && hasHistory {
+		return Pool{}, err
+	}
+
+	p := Pool{Name: name, Total: len(statuses)}
+	for _, s := range statuses {
+		slot := Slot{Name: s.SlotName, Phase: s.Phase(), Reason: s.Reason}
+		if (s.Claimed || s.Expired) && s.Claim != nil {
+			claimedAt, expiresAt := s.Claim.ClaimedAt, s.Claim.ExpiresAt
+			slot.Holder = s.Claim.Holder
+			slot.ClaimedAt = &claimedAt
+			slot.ExpiresAt = &expiresAt
+			slot.Annotations = s.Claim.Annotations
+		}
+		if s.Claimed {
+			p.Claimed++
+		}
+		p.Slots = append(p.Slots, slot)
+	}
+
+	if hasHistory {
+		p.Utilization = utilization(statuses, events, now)
+	}
+	return p, nil
+}
+
+// utilization reconstructs the fraction of claimed slots at the end of each
+// bucket of the last Window by walking the pool's history back from its
+// current status.
+func utilization(statuses []lockstore.SlotStatus, events []lockstore.Event, now time.Time) []float64 {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	claimed := 0
+	for _, s := range statuses {
+		if s.Claimed {
+			claimed++
+		}
+		// A lapsed lease that hasn't been reaped has no expire event yet
+		if s.Expired && s.Claim != nil {
+			events = append(events, lockstore.Event{Kind: lockstore.EventExpire, At: s.Claim.ExpiresAt})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.After(events[j].At) })
+
+	step := Window / buckets
+	points := make([]float64, buckets)
+	next := 0
+	for i := buckets - 1; i >= 0; i-- {
+		end := now.Add(-time.Duration(buckets-1-i) * step)
+
+		// Undo every event after the end of this bucket
+		for ; next < len(events) && events[next].At.After(end); next++ {
+			switch events[next].Kind {
+			case lockstore.EventClaim:
+				claimed--
+			case lockstore.EventRelease, lockstore.EventExpire, lockstore.EventRevoke:
+				claimed++
+			}
+		}
+
+		points[i] = float64(min(max(claimed, 0), len(statuses))) / float64(len(statuses))
+	}
+	return points
+}
+
+// sparkline returns the SVG polyline points for a utilization series.
+func sparkline(points []float64) string {
+	if len(points) < 2 {
+		return ""
+	}
+
+	coords := make([]string, len(points))
+	for i, p := range points {
+		x := float64(i) * sparkWidth / float64(len(points)-1)
+		y := sparkHeight - p*sparkHeight
+		coords[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return strings.Join(coords, " ")
+}
@@ -0,0 +1,139 @@
+package dashboard_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/dashboard"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+)
+
+func TestDashboardShowsHoldersWithoutSecrets(t *testing.T) {
+	ss := secretmem.New()
+	e := &engine.Engine{
+		Cfg: &config.Config{
+			Pools: map[string]config.PoolConfig{
+				"testpool": {
+					Keys:  []string{"SHOPIFY_API_KEY"},
+					Slots: []config.SlotConfig{{Name: "alpha"}, {Name: "beta"}},
+					TTL:   time.Hour,
+				},
+			},
+		},
+		LockStore:   lockmem.New(),
+		SecretStore: ss,
+		Identity:    "test-holder",
+	}
+	ctx := context.Background()
+
+	lf, err := e.Claim(ctx, "testpool", map[string]string{"branch": "feature-x"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := ss.Write(ctx, config.SecretName(lf.SlotName, "SHOPIFY_API_KEY"), "super-secret"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	srv := httptest.NewServer(dashboard.New(e))
+	defer srv.Close()
+
+	page := get(t, srv.URL+"/")
+	for _, want := range []string{"testpool", "1/2 claimed", "test-holder", "branch=feature-x", "<polyline"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+
+	api := get(t, srv.URL+"/api/pools")
+	for _, body := range []string{page, api} {
+		if strings.Contains(body, lf.LeaseID) || strings.Contains(body, "super-secret") {
+			t.Fatalf("dashboard exposed a lease ID or secret value:\n%s", body)
+		}
+	}
+
+	var pools []dashboard.Pool
+	if err := json.Unmarshal([]byte(api), &pools); err != nil {
+		t.Fatalf("decoding /api/pools: %v", err)
+	}
+	if len(pools) != 1 || pools[0].Claimed != 1 || pools[0].Total != 2 {
+		t.Fatalf("unexpected pools: %+v", pools)
+	}
+	// The claim was just made, so only the latest point is utilized
+	util := pools[0].Utilization
+	if util[0] != 0 || util[len(util)-1] != 0.5 {
+		t.Errorf("utilization = %v, want 0 rising to 0.5", util)
+	}
+}
+
+// noHistoryStore is a lock store without history, like the http backend.
+type noHistoryStore struct {
+	*lockmem.Store
+}
+
+func (noHistoryStore) History(context.Context, string, []string, time.Time) ([]lockstore.Event, error) {
+	return nil, fmt.Errorf("history is not available: %w", errors.ErrUnsupported)
+}
+
+func TestDashboardWithoutHistory(t *testing.T) {
+	e := &engine.Engine{
+		Cfg: &config.Config{
+			Pools: map[string]config.PoolConfig{
+				"testpool": {
+					Keys:  []string{"SHOPIFY_API_KEY"},
+					Slots: []config.SlotConfig{{Name: "alpha"}},
+					TTL:   time.Hour,
+				},
+			},
+		},
+		LockStore:   noHistoryStore{lockmem.New()},
+		SecretStore: secretmem.New(),
+		Identity:    "test-holder",
+	}
+
+	srv := httptest.NewServer(dashboard.New(e))
+	defer srv.Close()
+
+	page := get(t, srv.URL+"/")
+	if !strings.Contains(page, "0/1 claimed") || strings.Contains(page, "<polyline") {
+		t.Errorf("expected the pool without a sparkline:\n%s", page)
+	}
+
+	var pools []dashboard.Pool
+	if err := json.Unmarshal([]byte(get(t, srv.URL+"/api/pools")), &pools); err != nil {
+		t.Fatalf("decoding /api/pools: %v", err)
+	}
+	if len(pools) != 1 || pools[0].Utilization != nil {
+		t.Errorf("expected one pool without utilization, got %+v", pools)
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading %s: %v", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s\n%s", url, resp.Status, body)
+	}
+	return string(body)
+}
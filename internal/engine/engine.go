@@ -2,20 +2,60 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
 
+	"github.com/Kashuab/claimenv/internal/audit"
 	"github.com/Kashuab/claimenv/internal/config"
-	"github.com/Kashuab/claimenv/internal/lease"
-	"github.com/Kashuab/claimenv/internal/lockstore"
-	"github.com/Kashuab/claimenv/internal/secretstore"
+	"github.com/Kashuab/claimenv/internal/notify"
+	"github.com/Kashuab/claimenv/internal/tracing"
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/Kashuab/claimenv/internal/engine")
+
 type Engine struct {
 	Cfg         *config.Config
 	LockStore   lockstore.LockStore
 	SecretStore secretstore.SecretStore
 	Identity    string
 	LeaseFile   string
+
+	// Audit receives an event for every secret read and write. Nil disables auditing.
+	Audit audit.Sink
+
+	// Notifier receives pool events such as exhaustion. Nil disables notifications.
+	Notifier notify.Notifier
+
+	// Log receives hook output and warnings. Defaults to os.Stderr.
+	Log io.Writer
+}
+
+// log returns the writer for hook output and warnings.
+func (e *Engine) log() io.Writer {
+	if e.Log == nil {
+		return os.Stderr
+	}
+	return e.Log
+}
+
+// leaseAttrs returns the span attributes identifying a lease, leaving out the
+// lease ID.
+func leaseAttrs(lf *lease.LeaseFile) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		tracing.PoolKey.String(lf.Pool),
+		tracing.SlotKey.String(lf.SlotName),
+		tracing.HolderKey.String(lf.Holder),
+	}
 }
 
 func (e *Engine) poolConfig(poolName string) (*config.PoolConfig, error) {
@@ -26,18 +66,89 @@ func (e *Engine) poolConfig(poolName string) (*config.PoolConfig, error) {
 	return &pool, nil
 }
 
+// claimOptions returns the pool policy enforced by the lock store on claim.
+func claimOptions(poolName string, pool *config.PoolConfig) (lockstore.ClaimOptions, error) {
+	opts := lockstore.ClaimOptions{
+		Cooldown: pool.Cooldown,
+	}
+
+	for i, q := range pool.Quotas {
+		pattern, err := regexp.Compile(q.Match)
+		if err != nil {
+			return opts, fmt.Errorf("pool %q: quota %d: invalid match pattern: %w", poolName, i, err)
+		}
+		name := q.Name
+		if name == "" {
+			name = q.Match
+		}
+		opts.Quotas = append(opts.Quotas, lockstore.Quota{Name: name, Pattern: pattern, Max: q.Max})
+	}
+
+	return opts, nil
+}
+
 // Claim acquires a free slot in the named pool and returns a LeaseFile.
-func (e *Engine) Claim(ctx context.Context, poolName string) (*lease.LeaseFile, error) {
+// The annotations (e.g. MR URL, branch) are stored on the claim and shown in status.
+func (e *Engine) Claim(ctx context.Context, poolName string, annotations map[string]string) (_ *lease.LeaseFile, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Claim", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.HolderKey.String(e.Identity)))
+	defer tracing.End(span, &err)
+
 	pool, err := e.poolConfig(poolName)
 	if err != nil {
 		return nil, err
 	}
 
-	claim, err := e.LockStore.Claim(ctx, poolName, pool.SlotNames(), e.Identity, pool.TTL)
+	opts, err := claimOptions(poolName, pool)
 	if err != nil {
 		return nil, err
 	}
+	opts.Annotations = annotations
+
+	// Reap first, so a slot whose lease expired is reset, has its post_release
+	// hook run and its holder notified before it's handed out again. The http
+	// store leaves reaping to its server.
+	if _, err := e.Reap(ctx, poolName); err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		fmt.Fprintf(e.log(), "Warning: %v\n", err)
+	}
+
+	var claim *lockstore.Claim
+	for {
+		claim, err = e.LockStore.Claim(ctx, poolName, pool.SlotNames(), e.Identity, pool.TTL, opts)
+		if err != nil {
+			if errors.Is(err, lockstore.ErrPoolExhausted) {
+				e.notify(ctx, notify.Event{Type: notify.PoolExhausted, Pool: poolName, Holder: e.Identity})
+			}
+			return nil, err
+		}
+		span.SetAttributes(tracing.SlotKey.String(claim.SlotName))
+
+		// A repeat claim hands back the holder's lease, which already had its hook
+		if claim.Existing {
+			return leaseFile(pool, claim), nil
+		}
 
+		ok, err := e.checkBaselines(ctx, pool, claim)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+	}
+
+	if err := e.runHook(ctx, pool, hookPostClaim, pool.Hooks.PostClaim, claim); err != nil {
+		// Don't leave the slot claimed by a holder that never got a lease file
+		if relErr := e.LockStore.Release(ctx, claim.Pool, claim.LeaseID); relErr != nil {
+			return nil, fmt.Errorf("%w (releasing the claim also failed: %v)", err, relErr)
+		}
+		return nil, fmt.Errorf("%w; the claim was released", err)
+	}
+
+	return leaseFile(pool, claim), nil
+}
+
+// leaseFile returns the lease file for an active claim in pool.
+func leaseFile(pool *config.PoolConfig, claim *lockstore.Claim) *lease.LeaseFile {
 	return &lease.LeaseFile{
 		Pool:      claim.Pool,
 		SlotName:  claim.SlotName,
@@ -46,30 +157,229 @@ func (e *Engine) Claim(ctx context.Context, poolName string) (*lease.LeaseFile,
 		Holder:    claim.Holder,
 		ClaimedAt: claim.ClaimedAt,
 		ExpiresAt: claim.ExpiresAt,
-	}, nil
+
+		Annotations: claim.Annotations,
+		Existing:    claim.Existing,
+	}
+}
+
+// Lease rebuilds the lease file of an active lease from the lock store, for
+// callers that only know the lease ID.
+func (e *Engine) Lease(ctx context.Context, poolName, leaseID string) (_ *lease.LeaseFile, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Lease", trace.WithAttributes(tracing.PoolKey.String(poolName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := e.LockStore.ValidateLease(ctx, poolName, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaseFile(pool, claim), nil
+}
+
+// Attach rebuilds the lease file of the active claim this engine's identity
+// holds in the named pool, for a job that didn't get the lease file from the
+// job that claimed it.
+func (e *Engine) Attach(ctx context.Context, poolName string) (_ *lease.LeaseFile, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Attach", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.HolderKey.String(e.Identity)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := e.LockStore.FindByHolder(ctx, poolName, e.Identity)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(tracing.SlotKey.String(claim.SlotName))
+
+	return leaseFile(pool, claim), nil
 }
 
 // Release releases the claim described by the lease file.
-func (e *Engine) Release(ctx context.Context, lf *lease.LeaseFile) error {
-	if _, err := e.LockStore.ValidateLease(ctx, lf.Pool, lf.LeaseID); err != nil {
+func (e *Engine) Release(ctx context.Context, lf *lease.LeaseFile) (err error) {
+	ctx, span := tracer.Start(ctx, "engine.Release", trace.WithAttributes(leaseAttrs(lf)...))
+	defer tracing.End(span, &err)
+
+	claim, err := e.LockStore.ValidateLease(ctx, lf.Pool, lf.LeaseID)
+	if err != nil {
 		return fmt.Errorf("lease validation failed: %w", err)
 	}
 
-	return e.LockStore.Release(ctx, lf.Pool, lf.LeaseID)
+	return e.release(ctx, claim, e.LockStore.Release)
 }
 
 // ReleaseByHolder releases the slot held by this engine's identity in the named pool.
 // This does not require a lease file.
-func (e *Engine) ReleaseByHolder(ctx context.Context, poolName string) error {
+func (e *Engine) ReleaseByHolder(ctx context.Context, poolName string) (err error) {
+	ctx, span := tracer.Start(ctx, "engine.ReleaseByHolder", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.HolderKey.String(e.Identity)))
+	defer tracing.End(span, &err)
+
 	if _, err := e.poolConfig(poolName); err != nil {
 		return err
 	}
 
-	return e.LockStore.ReleaseByHolder(ctx, poolName, e.Identity)
+	claim, err := e.LockStore.FindByHolder(ctx, poolName, e.Identity)
+	if err != nil {
+		return err
+	}
+
+	return e.release(ctx, claim, e.LockStore.Release)
+}
+
+// Leases returns the active claims held by this engine's identity in every
+// pool, ordered by pool name. Like ReleaseByHolder, it doesn't need a lease
+// file, so a holder can find what it still holds after losing one.
+func (e *Engine) Leases(ctx context.Context) (_ []*lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Leases", trace.WithAttributes(tracing.HolderKey.String(e.Identity)))
+	defer tracing.End(span, &err)
+
+	var claims []*lockstore.Claim
+	for _, poolName := range e.Cfg.PoolNames() {
+		claim, err := e.LockStore.FindByHolder(ctx, poolName, e.Identity)
+		if errors.Is(err, lockstore.ErrLeaseNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pool %q: %w", poolName, err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+// Revoke forcibly releases whatever claim is active on a slot, regardless of
+// its holder. It goes through the same hooks and reset as a regular release,
+// but is recorded as a revocation in the slot's history.
+func (e *Engine) Revoke(ctx context.Context, poolName, slotName string) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Revoke", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.SlotKey.String(slotName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.HasSlot(slotName) {
+		return nil, fmt.Errorf("slot %q not found in pool %q", slotName, poolName)
+	}
+
+	statuses, err := e.LockStore.Status(ctx, poolName, []string{slotName})
+	if err != nil {
+		return nil, err
+	}
+	if !statuses[0].Claimed || statuses[0].Claim == nil {
+		return nil, fmt.Errorf("slot %q in pool %q has no active claim", slotName, poolName)
+	}
+
+	claim := statuses[0].Claim
+	if err := e.release(ctx, claim, e.LockStore.Revoke); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// release frees a validated claim with free, running the pool's release hooks
+// around it and restoring resettable keys first. If the pre_release hook or the
+// reset fails, the slot stays claimed.
+func (e *Engine) release(ctx context.Context, claim *lockstore.Claim, free func(ctx context.Context, pool string, leaseID string) error) error {
+	pool, err := e.poolConfig(claim.Pool)
+	if err != nil {
+		return err
+	}
+
+	if err := e.runHook(ctx, pool, hookPreRelease, pool.Hooks.PreRelease, claim); err != nil {
+		return err
+	}
+
+	if err := e.resetSlot(ctx, pool, claim); err != nil {
+		return err
+	}
+
+	if err := free(ctx, claim.Pool, claim.LeaseID); err != nil {
+		return err
+	}
+
+	return e.runHook(ctx, pool, hookPostRelease, pool.Hooks.PostRelease, claim)
+}
+
+// resetSlot rewrites the pool's resettable keys in the slot to their stored
+// baseline values, so the next claimant starts from a clean slate. A missing
+// baseline is skipped with a warning rather than leaving the slot claimed for
+// good; Claim won't hand the slot out again until the baseline exists.
+func (e *Engine) resetSlot(ctx context.Context, pool *config.PoolConfig, claim *lockstore.Claim) error {
+	slotName := claim.SlotName
+	for _, key := range pool.Resettable {
+		if err := e.auditClaim(ctx, audit.ActionRead, claim, key, actorReset); err != nil {
+			return err
+		}
+		baseline, err := e.SecretStore.Read(ctx, config.BaselineSecretName(slotName, key))
+		if errors.Is(err, secretstore.ErrSecretNotFound) {
+			// Claim quarantines the slot before anyone gets the stale value
+			fmt.Fprintf(e.log(), "Warning: no baseline for key %q in slot %q; it was not reset\n", key, slotName)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read baseline for key %q in slot %q: %w", key, slotName, err)
+		}
+
+		if err := e.auditClaim(ctx, audit.ActionWrite, claim, key, actorReset); err != nil {
+			return err
+		}
+		if err := e.SecretStore.Write(ctx, config.SecretName(slotName, key), baseline); err != nil {
+			return fmt.Errorf("failed to reset key %q in slot %q: %w", key, slotName, err)
+		}
+	}
+	return nil
+}
+
+// checkBaselines reports whether every resettable key of a newly claimed slot
+// has a baseline, so the slot can be reset on release. A slot missing one is
+// released and quarantined with the missing baseline as the reason, and the
+// caller should claim another slot.
+func (e *Engine) checkBaselines(ctx context.Context, pool *config.PoolConfig, claim *lockstore.Claim) (bool, error) {
+	for _, key := range pool.Resettable {
+		name := config.BaselineSecretName(claim.SlotName, key)
+		err := e.auditClaim(ctx, audit.ActionRead, claim, key, actorReset)
+		if err == nil {
+			_, err = e.SecretStore.Read(ctx, name)
+		}
+		if err == nil {
+			continue
+		}
+
+		if relErr := e.LockStore.Release(ctx, claim.Pool, claim.LeaseID); relErr != nil {
+			return false, fmt.Errorf("failed to read baseline for key %q in slot %q: %w (releasing the claim also failed: %v)", key, claim.SlotName, err, relErr)
+		}
+		if !errors.Is(err, secretstore.ErrSecretNotFound) {
+			return false, fmt.Errorf("failed to read baseline for key %q in slot %q: %w; the claim was released", key, claim.SlotName, err)
+		}
+
+		reason := fmt.Sprintf("missing baseline secret %s", name)
+		if err := e.LockStore.SetSlotState(ctx, claim.Pool, claim.SlotName, lockstore.SlotQuarantined, reason); err != nil {
+			return false, fmt.Errorf("slot %q has no baseline for key %q, and quarantining it failed: %w", claim.SlotName, key, err)
+		}
+		e.notify(ctx, notify.Event{Type: notify.SlotQuarantined, Pool: claim.Pool, Slot: claim.SlotName, Reason: reason})
+		fmt.Fprintf(e.log(), "Warning: quarantined slot %q: %s\n", claim.SlotName, reason)
+		return false, nil
+	}
+	return true, nil
 }
 
 // ReadKey reads a single env var value from the claimed slot.
-func (e *Engine) ReadKey(ctx context.Context, lf *lease.LeaseFile, key string) (string, error) {
+func (e *Engine) ReadKey(ctx context.Context, lf *lease.LeaseFile, key string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "engine.ReadKey", trace.WithAttributes(append(leaseAttrs(lf), tracing.EnvKey.String(key))...))
+	defer tracing.End(span, &err)
+
+	// Remote secret stores authorize the read by the lease
+	ctx = lease.NewContext(ctx, lf)
+
 	secretName, ok := lf.Secrets[key]
 	if !ok {
 		return "", fmt.Errorf("key %q is not defined in this slot's secrets", key)
@@ -79,17 +389,30 @@ func (e *Engine) ReadKey(ctx context.Context, lf *lease.LeaseFile, key string) (
 		return "", fmt.Errorf("lease validation failed: %w", err)
 	}
 
+	if err := e.audit(ctx, audit.ActionRead, lf, key); err != nil {
+		return "", err
+	}
+
 	return e.SecretStore.Read(ctx, secretName)
 }
 
 // ReadAll reads all env var values from the claimed slot.
-func (e *Engine) ReadAll(ctx context.Context, lf *lease.LeaseFile) (map[string]string, error) {
+func (e *Engine) ReadAll(ctx context.Context, lf *lease.LeaseFile) (_ map[string]string, err error) {
+	ctx, span := tracer.Start(ctx, "engine.ReadAll", trace.WithAttributes(leaseAttrs(lf)...))
+	defer tracing.End(span, &err)
+
+	ctx = lease.NewContext(ctx, lf)
+
 	if _, err := e.LockStore.ValidateLease(ctx, lf.Pool, lf.LeaseID); err != nil {
 		return nil, fmt.Errorf("lease validation failed: %w", err)
 	}
 
 	result := make(map[string]string, len(lf.Secrets))
 	for key, secretName := range lf.Secrets {
+		if err := e.audit(ctx, audit.ActionRead, lf, key); err != nil {
+			return nil, err
+		}
+
 		val, err := e.SecretStore.Read(ctx, secretName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read secret for key %q: %w", key, err)
@@ -100,7 +423,12 @@ func (e *Engine) ReadAll(ctx context.Context, lf *lease.LeaseFile) (map[string]s
 }
 
 // WriteKey writes a single env var to the claimed slot.
-func (e *Engine) WriteKey(ctx context.Context, lf *lease.LeaseFile, key, value string) error {
+func (e *Engine) WriteKey(ctx context.Context, lf *lease.LeaseFile, key, value string) (err error) {
+	ctx, span := tracer.Start(ctx, "engine.WriteKey", trace.WithAttributes(append(leaseAttrs(lf), tracing.EnvKey.String(key))...))
+	defer tracing.End(span, &err)
+
+	ctx = lease.NewContext(ctx, lf)
+
 	secretName, ok := lf.Secrets[key]
 	if !ok {
 		return fmt.Errorf("key %q is not defined in this slot's secrets", key)
@@ -110,9 +438,47 @@ func (e *Engine) WriteKey(ctx context.Context, lf *lease.LeaseFile, key, value s
 		return fmt.Errorf("lease validation failed: %w", err)
 	}
 
+	if err := e.audit(ctx, audit.ActionWrite, lf, key); err != nil {
+		return err
+	}
+
 	return e.SecretStore.Write(ctx, secretName, value)
 }
 
+// audit records a secret access before it happens. Access is refused if the
+// event can't be recorded, so the audit trail never misses a read or write.
+func (e *Engine) audit(ctx context.Context, action audit.Action, lf *lease.LeaseFile, key string) error {
+	return e.auditClaim(ctx, action, &lockstore.Claim{Pool: lf.Pool, SlotName: lf.SlotName, LeaseID: lf.LeaseID, Holder: lf.Holder}, key, "")
+}
+
+// actorReset is the audit actor of the reads and writes that restore resettable
+// keys, including the baseline check before a slot is handed out.
+const actorReset = "reset"
+
+// auditClaim records a secret access of claim's slot made by actor, such as a
+// hook or the reset of a resettable key, rather than directly by the caller.
+func (e *Engine) auditClaim(ctx context.Context, action audit.Action, claim *lockstore.Claim, key, actor string) error {
+	if e.Audit == nil {
+		return nil
+	}
+
+	err := e.Audit.Emit(ctx, audit.Event{
+		Time:     time.Now(),
+		Action:   action,
+		Pool:     claim.Pool,
+		Slot:     claim.SlotName,
+		Key:      key,
+		LeaseID:  claim.LeaseID,
+		Holder:   claim.Holder,
+		Identity: e.Identity,
+		Actor:    actor,
+	})
+	if err != nil {
+		return fmt.Errorf("audit log unavailable, refusing %s of key %q: %w", action, key, err)
+	}
+	return nil
+}
+
 // SecretName returns the GCP Secret Manager secret name for a key without reading the value.
 func (e *Engine) SecretName(lf *lease.LeaseFile, key string) (string, error) {
 	secretName, ok := lf.Secrets[key]
@@ -123,13 +489,17 @@ func (e *Engine) SecretName(lf *lease.LeaseFile, key string) (string, error) {
 }
 
 // Renew extends the TTL on the current claim and returns updated lease info.
-func (e *Engine) Renew(ctx context.Context, lf *lease.LeaseFile) (*lease.LeaseFile, error) {
+// Claims are never extended past the pool's max_lease.
+func (e *Engine) Renew(ctx context.Context, lf *lease.LeaseFile) (_ *lease.LeaseFile, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Renew", trace.WithAttributes(leaseAttrs(lf)...))
+	defer tracing.End(span, &err)
+
 	pool, err := e.poolConfig(lf.Pool)
 	if err != nil {
 		return nil, err
 	}
 
-	claim, err := e.LockStore.Renew(ctx, lf.Pool, lf.LeaseID, pool.TTL)
+	claim, err := e.LockStore.Renew(ctx, lf.Pool, lf.LeaseID, pool.TTL, pool.MaxLease)
 	if err != nil {
 		return nil, err
 	}
@@ -142,17 +512,385 @@ func (e *Engine) Renew(ctx context.Context, lf *lease.LeaseFile) (*lease.LeaseFi
 		Holder:    claim.Holder,
 		ClaimedAt: claim.ClaimedAt,
 		ExpiresAt: claim.ExpiresAt,
+
+		Annotations: claim.Annotations,
 	}, nil
 }
 
+// Reap clears expired leases in the named pool and returns the lapsed claims,
+// so callers can report which holders let their leases run out.
+// Every reaped slot has its resettable keys restored before it's freed, and
+// the post_release hook run after; the lease is already gone, so pre_release
+// is skipped. A slot that fails to reset stays expired, and so unclaimable,
+// until a later reap resets it. Failures are reported after all slots are
+// reaped.
+func (e *Engine) Reap(ctx context.Context, poolName string) (_ []lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Reap", trace.WithAttributes(tracing.PoolKey.String(poolName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	slotNames := pool.SlotNames()
+	if len(pool.Resettable) > 0 {
+		statuses, err := e.LockStore.Status(ctx, poolName, slotNames)
+		if err != nil {
+			return nil, err
+		}
+
+		slotNames = nil
+		for i := range statuses {
+			s := &statuses[i]
+			if !s.Expired || s.Claim == nil {
+				continue
+			}
+			if err := e.resetSlot(ctx, pool, s.Claim); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			slotNames = append(slotNames, s.SlotName)
+		}
+	}
+
+	var reaped []lockstore.Claim
+	if len(slotNames) > 0 {
+		reaped, err = e.LockStore.Reap(ctx, poolName, slotNames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range reaped {
+		c := &reaped[i]
+		expiresAt := c.ExpiresAt
+		e.notify(ctx, notify.Event{Type: notify.LeaseExpired, Pool: poolName, Slot: c.SlotName, Holder: c.Holder, ExpiresAt: &expiresAt})
+
+		if err := e.runHook(ctx, pool, hookPostRelease, pool.Hooks.PostRelease, c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return reaped, fmt.Errorf("reap errors: %v", errs)
+	}
+
+	return reaped, nil
+}
+
+// Reserve books a slot in the named pool for holder, starting at from and
+// lasting for d. Other holders can't claim the slot in a way that overlaps the
+// window, and holder's claim during the window always gets the reserved slot.
+func (e *Engine) Reserve(ctx context.Context, poolName, holder string, from time.Time, d time.Duration) (_ *lockstore.Reservation, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Reserve", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.HolderKey.String(holder)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("reservation duration must be > 0")
+	}
+	until := from.Add(d)
+	if !until.After(time.Now()) {
+		return nil, fmt.Errorf("reservation window ends in the past")
+	}
+
+	return e.LockStore.Reserve(ctx, poolName, pool.SlotNames(), holder, from, until)
+}
+
+// CancelReservation removes a reservation from the named pool.
+func (e *Engine) CancelReservation(ctx context.Context, poolName, reservationID string) (err error) {
+	ctx, span := tracer.Start(ctx, "engine.CancelReservation", trace.WithAttributes(tracing.PoolKey.String(poolName)))
+	defer tracing.End(span, &err)
+
+	if _, err := e.poolConfig(poolName); err != nil {
+		return err
+	}
+
+	return e.LockStore.CancelReservation(ctx, poolName, reservationID)
+}
+
+// History returns the lease events of the named pool at or after since, oldest
+// first. If slotName is empty, events of every slot in the pool are returned.
+func (e *Engine) History(ctx context.Context, poolName, slotName string, since time.Time) (_ []lockstore.Event, err error) {
+	ctx, span := tracer.Start(ctx, "engine.History", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.SlotKey.String(slotName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	slotNames := pool.SlotNames()
+	if slotName != "" {
+		if !pool.HasSlot(slotName) {
+			return nil, fmt.Errorf("slot %q not found in pool %q", slotName, poolName)
+		}
+		slotNames = []string{slotName}
+	}
+
+	return e.LockStore.History(ctx, poolName, slotNames, since)
+}
+
 // Status returns the status of all slots in the named pool.
-func (e *Engine) Status(ctx context.Context, poolName string) ([]lockstore.SlotStatus, error) {
+// Free slots still within the pool's cooldown have CoolingUntil set.
+func (e *Engine) Status(ctx context.Context, poolName string) (_ []lockstore.SlotStatus, err error) {
+	ctx, span := tracer.Start(ctx, "engine.Status", trace.WithAttributes(tracing.PoolKey.String(poolName)))
+	defer tracing.End(span, &err)
+
 	pool, err := e.poolConfig(poolName)
 	if err != nil {
 		return nil, err
 	}
 
-	return e.LockStore.Status(ctx, poolName, pool.SlotNames())
+	statuses, err := e.LockStore.Status(ctx, poolName, pool.SlotNames())
+	if err != nil {
+		return nil, err
+	}
+
+	markCooling(pool, statuses, time.Now())
+	return statuses, nil
+}
+
+// markCooling sets CoolingUntil on the free slots still within the pool's
+// cooldown at now.
+func markCooling(pool *config.PoolConfig, statuses []lockstore.SlotStatus, now time.Time) {
+	if pool.Cooldown <= 0 {
+		return
+	}
+
+	for i := range statuses {
+		s := &statuses[i]
+		var freedAt time.Time
+		switch {
+		case s.Claimed:
+			continue
+		case s.Expired && s.Claim != nil:
+			freedAt = s.Claim.ExpiresAt
+		case s.ReleasedAt != nil:
+			freedAt = *s.ReleasedAt
+		default:
+			continue
+		}
+		if until := freedAt.Add(pool.Cooldown); now.Before(until) {
+			s.CoolingUntil = &until
+		}
+	}
+}
+
+// Watch calls fn with the status of all slots in the named pool, as Status
+// returns it, first as it is now and then whenever it changes, until ctx is
+// done or fn returns an error. Lock stores implementing lockstore.Watcher push
+// changes; others are polled every interval.
+func (e *Engine) Watch(ctx context.Context, poolName string, interval time.Duration, fn func([]lockstore.SlotStatus) error) error {
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return err
+	}
+
+	w, ok := e.LockStore.(lockstore.Watcher)
+	if !ok {
+		return e.poll(ctx, poolName, interval, fn)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan []lockstore.SlotStatus)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.Watch(watchCtx, poolName, pool.SlotNames(), func(statuses []lockstore.SlotStatus) error {
+			select {
+			case updates <- statuses:
+				return nil
+			case <-watchCtx.Done():
+				return watchCtx.Err()
+			}
+		})
+	}()
+
+	// Leases expiring and cooldowns ending don't change the store, so the
+	// last statuses are refreshed when the next of them is due
+	var latest []lockstore.SlotStatus
+	var due <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errCh:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case latest = <-updates:
+		case <-due:
+		}
+
+		now := time.Now()
+		statuses := refreshStatuses(pool, latest, now)
+		due = nil
+		if next := nextTransition(statuses, now); !next.IsZero() {
+			due = time.After(next.Sub(now))
+		}
+
+		if err := fn(statuses); err != nil {
+			return err
+		}
+	}
+}
+
+// poll calls fn with the pool's status every interval.
+func (e *Engine) poll(ctx context.Context, poolName string, interval time.Duration, fn func([]lockstore.SlotStatus) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		statuses, err := e.Status(ctx, poolName)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := fn(statuses); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshStatuses returns a copy of statuses with the lease and cooldown
+// fields recomputed for now.
+func refreshStatuses(pool *config.PoolConfig, statuses []lockstore.SlotStatus, now time.Time) []lockstore.SlotStatus {
+	refreshed := make([]lockstore.SlotStatus, len(statuses))
+	for i, s := range statuses {
+		if s.Claim != nil {
+			s.Claimed = now.Before(s.Claim.ExpiresAt)
+			s.Expired = !s.Claimed
+		}
+		s.CoolingUntil = nil
+		refreshed[i] = s
+	}
+
+	markCooling(pool, refreshed, now)
+	return refreshed
+}
+
+// nextTransition returns the earliest time after now at which a lease in
+// statuses expires or a cooldown ends, or the zero time if there is none.
+func nextTransition(statuses []lockstore.SlotStatus, now time.Time) time.Time {
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	for _, s := range statuses {
+		if s.Claimed && s.Claim != nil {
+			consider(s.Claim.ExpiresAt)
+		}
+		if s.CoolingUntil != nil {
+			consider(*s.CoolingUntil)
+		}
+	}
+	return next
+}
+
+// SetSlotState changes the administrative state of a slot in the named pool.
+// Draining slots finish their current lease, renewals included, but accept no
+// new claims. Quarantined slots are never claimable until restored to active,
+// and their active claim is revoked as by Revoke, which is returned.
+func (e *Engine) SetSlotState(ctx context.Context, poolName, slotName string, state lockstore.SlotState, reason string) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "engine.SetSlotState", trace.WithAttributes(tracing.PoolKey.String(poolName), tracing.SlotKey.String(slotName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.HasSlot(slotName) {
+		return nil, fmt.Errorf("slot %q not found in pool %q", slotName, poolName)
+	}
+
+	if err := e.LockStore.SetSlotState(ctx, poolName, slotName, state, reason); err != nil {
+		return nil, err
+	}
+
+	if state != lockstore.SlotQuarantined {
+		return nil, nil
+	}
+	e.notify(ctx, notify.Event{Type: notify.SlotQuarantined, Pool: poolName, Slot: slotName, Reason: reason})
+
+	// Pull the slot out of rotation now, not when its holder lets go
+	statuses, err := e.LockStore.Status(ctx, poolName, []string{slotName})
+	if err != nil {
+		return nil, fmt.Errorf("slot %q was quarantined, but checking for its claim failed: %w", slotName, err)
+	}
+	if !statuses[0].Claimed || statuses[0].Claim == nil {
+		return nil, nil
+	}
+
+	claim := statuses[0].Claim
+	if err := e.release(ctx, claim, e.LockStore.Revoke); err != nil {
+		return nil, fmt.Errorf("slot %q was quarantined, but revoking its claim failed: %w", slotName, err)
+	}
+	return claim, nil
+}
+
+// WarnExpiring sends a lease_expiring notification for every claim in the
+// named pool that expires within the pool's expiry_warning, and returns those
+// claims. It does nothing if the pool has no expiry_warning.
+func (e *Engine) WarnExpiring(ctx context.Context, poolName string) (_ []lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "engine.WarnExpiring", trace.WithAttributes(tracing.PoolKey.String(poolName)))
+	defer tracing.End(span, &err)
+
+	pool, err := e.poolConfig(poolName)
+	if err != nil {
+		return nil, err
+	}
+	if pool.ExpiryWarning <= 0 {
+		return nil, nil
+	}
+
+	statuses, err := e.LockStore.Status(ctx, poolName, pool.SlotNames())
+	if err != nil {
+		return nil, err
+	}
+
+	var expiring []lockstore.Claim
+	deadline := time.Now().Add(pool.ExpiryWarning)
+	for _, s := range statuses {
+		if !s.Claimed || s.Claim == nil || s.Claim.ExpiresAt.After(deadline) {
+			continue
+		}
+		expiring = append(expiring, *s.Claim)
+
+		expiresAt := s.Claim.ExpiresAt
+		e.notify(ctx, notify.Event{Type: notify.LeaseExpiring, Pool: poolName, Slot: s.SlotName, Holder: s.Claim.Holder, ExpiresAt: &expiresAt})
+	}
+	return expiring, nil
+}
+
+// notify delivers ev to the configured notifier. Notifications are best
+// effort: a failure is reported on stderr but never fails the operation.
+func (e *Engine) notify(ctx context.Context, ev notify.Event) {
+	if e.Notifier == nil {
+		return
+	}
+
+	ev.Time = time.Now()
+	if err := e.Notifier.Notify(ctx, ev); err != nil {
+		fmt.Fprintf(e.log(), "Warning: %v\n", err)
+	}
 }
 
 // Close releases resources held by both stores.
@@ -164,6 +902,11 @@ func (e *Engine) Close() error {
 	if err := e.SecretStore.Close(); err != nil {
 		errs = append(errs, err)
 	}
+	if e.Audit != nil {
+		if err := e.Audit.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if len(errs) > 0 {
 		return fmt.Errorf("close errors: %v", errs)
 	}
@@ -2,14 +2,23 @@ package engine_test
 
 import (
 	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/Kashuab/claimenv/internal/audit"
 	"github.com/Kashuab/claimenv/internal/config"
 	"github.com/Kashuab/claimenv/internal/engine"
-	"github.com/Kashuab/claimenv/internal/lockstore"
-	lockmem "github.com/Kashuab/claimenv/internal/lockstore/memory"
-	secretmem "github.com/Kashuab/claimenv/internal/secretstore/memory"
+	"github.com/Kashuab/claimenv/internal/notify"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func testEngine() (*engine.Engine, *lockmem.Store, *secretmem.Store) {
@@ -44,7 +53,7 @@ func TestClaim(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -73,13 +82,13 @@ func TestClaimIdempotent(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf1, err := e.Claim(ctx, "testpool")
+	lf1, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("first claim failed: %v", err)
 	}
 
 	// Same holder claiming again should return the same slot
-	lf2, err := e.Claim(ctx, "testpool")
+	lf2, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("second claim (same holder) failed: %v", err)
 	}
@@ -98,20 +107,20 @@ func TestClaimExhaustsPool(t *testing.T) {
 
 	// Claim both slots with different holders
 	e.Identity = "holder-1"
-	_, err := e.Claim(ctx, "testpool")
+	_, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("first claim failed: %v", err)
 	}
 
 	e.Identity = "holder-2"
-	_, err = e.Claim(ctx, "testpool")
+	_, err = e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("second claim failed: %v", err)
 	}
 
 	// Third claim with a new holder should fail
 	e.Identity = "holder-3"
-	_, err = e.Claim(ctx, "testpool")
+	_, err = e.Claim(ctx, "testpool", nil)
 	if err != lockstore.ErrPoolExhausted {
 		t.Errorf("expected ErrPoolExhausted, got %v", err)
 	}
@@ -121,7 +130,7 @@ func TestClaimInvalidPool(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	_, err := e.Claim(ctx, "nonexistent")
+	_, err := e.Claim(ctx, "nonexistent", nil)
 	if err == nil {
 		t.Error("expected error for nonexistent pool")
 	}
@@ -131,7 +140,7 @@ func TestRelease(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -142,7 +151,7 @@ func TestRelease(t *testing.T) {
 	}
 
 	// Should be able to claim again
-	lf2, err := e.Claim(ctx, "testpool")
+	lf2, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("re-Claim failed: %v", err)
 	}
@@ -155,7 +164,7 @@ func TestReleaseByHolder(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	_, err := e.Claim(ctx, "testpool")
+	_, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -167,7 +176,7 @@ func TestReleaseByHolder(t *testing.T) {
 	}
 
 	// Should be able to claim again
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("re-Claim failed: %v", err)
 	}
@@ -186,6 +195,72 @@ func TestReleaseByHolderNotFound(t *testing.T) {
 	}
 }
 
+func TestAttach(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	if _, err := e.Attach(ctx, "testpool"); !errors.Is(err, lockstore.ErrLeaseNotFound) {
+		t.Fatalf("expected ErrLeaseNotFound before claiming, got %v", err)
+	}
+
+	claimed, err := e.Claim(ctx, "testpool", map[string]string{"branch": "feature-x"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	lf, err := e.Attach(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if lf.LeaseID != claimed.LeaseID || lf.SlotName != claimed.SlotName {
+		t.Errorf("expected lease %s on %s, got %s on %s", claimed.LeaseID, claimed.SlotName, lf.LeaseID, lf.SlotName)
+	}
+	if lf.Secrets["SHOPIFY_API_KEY"] != "alpha-shopify-api-key" {
+		t.Errorf("expected secret names to be filled in, got %v", lf.Secrets)
+	}
+	if lf.Annotations["branch"] != "feature-x" {
+		t.Errorf("expected annotations to be kept, got %v", lf.Annotations)
+	}
+}
+
+func TestLeasesAcrossPools(t *testing.T) {
+	e, _, _ := testEngine()
+	e.Cfg.Pools["otherpool"] = config.PoolConfig{
+		Keys:  []string{"API_KEY"},
+		Slots: []config.SlotConfig{{Name: "gamma"}},
+		TTL:   time.Hour,
+	}
+	ctx := context.Background()
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if _, err := e.Claim(ctx, "otherpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// Another holder's claim must not be listed
+	e.Identity = "other-holder"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	e.Identity = "test-holder"
+
+	claims, err := e.Leases(ctx)
+	if err != nil {
+		t.Fatalf("Leases failed: %v", err)
+	}
+	if len(claims) != 2 {
+		t.Fatalf("expected 2 claims, got %d", len(claims))
+	}
+	if claims[0].Pool != "otherpool" || claims[0].SlotName != "gamma" {
+		t.Errorf("expected otherpool/gamma first, got %s/%s", claims[0].Pool, claims[0].SlotName)
+	}
+	if claims[1].Pool != "testpool" || claims[1].SlotName != "alpha" {
+		t.Errorf("expected testpool/alpha second, got %s/%s", claims[1].Pool, claims[1].SlotName)
+	}
+}
+
 func TestReadWriteKey(t *testing.T) {
 	e, _, ss := testEngine()
 	ctx := context.Background()
@@ -193,7 +268,7 @@ func TestReadWriteKey(t *testing.T) {
 	// Seed the secret store with a value for the derived secret name
 	ss.Seed("alpha-shopify-api-key", "test-key-123")
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -227,7 +302,7 @@ func TestReadWriteKeyUndefinedKey(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -253,7 +328,7 @@ func TestReadAll(t *testing.T) {
 	ss.Seed("alpha-shopify-api-key", "val_a")
 	ss.Seed("alpha-app-url", "val_b")
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -278,7 +353,7 @@ func TestSecretName(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -301,7 +376,7 @@ func TestRenew(t *testing.T) {
 	e, _, _ := testEngine()
 	ctx := context.Background()
 
-	lf, err := e.Claim(ctx, "testpool")
+	lf, err := e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -346,7 +421,7 @@ func TestStatus(t *testing.T) {
 	}
 
 	// After one claim
-	_, err = e.Claim(ctx, "testpool")
+	_, err = e.Claim(ctx, "testpool", nil)
 	if err != nil {
 		t.Fatalf("Claim failed: %v", err)
 	}
@@ -362,3 +437,1166 @@ func TestStatus(t *testing.T) {
 		t.Error("expected slot 'beta' to be free")
 	}
 }
+
+func TestDrainKeepsLeaseButBlocksNewClaims(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if _, err := e.SetSlotState(ctx, "testpool", "alpha", lockstore.SlotDraining, ""); err != nil {
+		t.Fatalf("SetSlotState failed: %v", err)
+	}
+
+	// The current lease keeps working
+	if _, err := e.Renew(ctx, lf); err != nil {
+		t.Fatalf("Renew on draining slot failed: %v", err)
+	}
+
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	// A drained slot is skipped once free
+	lf2, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("re-Claim failed: %v", err)
+	}
+	if lf2.SlotName != "beta" {
+		t.Errorf("expected draining slot 'alpha' to be skipped, got %q", lf2.SlotName)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].State != lockstore.SlotDraining {
+		t.Errorf("expected slot 'alpha' to be draining, got %q", statuses[0].State)
+	}
+}
+
+func TestQuarantineAndRestore(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	if _, err := e.SetSlotState(ctx, "testpool", "alpha", lockstore.SlotQuarantined, "app suspended"); err != nil {
+		t.Fatalf("SetSlotState failed: %v", err)
+	}
+	if _, err := e.SetSlotState(ctx, "testpool", "beta", lockstore.SlotQuarantined, "app suspended"); err != nil {
+		t.Fatalf("SetSlotState failed: %v", err)
+	}
+
+	_, err := e.Claim(ctx, "testpool", nil)
+	if err != lockstore.ErrPoolExhausted {
+		t.Errorf("expected ErrPoolExhausted with all slots quarantined, got %v", err)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].State != lockstore.SlotQuarantined || statuses[0].Reason != "app suspended" {
+		t.Errorf("expected quarantined slot with reason, got %q (%q)", statuses[0].State, statuses[0].Reason)
+	}
+
+	if _, err := e.SetSlotState(ctx, "testpool", "alpha", lockstore.SlotActive, ""); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim after restore failed: %v", err)
+	}
+	if lf.SlotName != "alpha" {
+		t.Errorf("expected restored slot 'alpha', got %q", lf.SlotName)
+	}
+}
+
+func TestQuarantineRevokesActiveClaim(t *testing.T) {
+	e, _, ss := testEngine()
+	ctx := context.Background()
+	ss.Seed("alpha-shopify-api-key", "test-key-123")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	revoked, err := e.SetSlotState(ctx, "testpool", "alpha", lockstore.SlotQuarantined, "app suspended")
+	if err != nil {
+		t.Fatalf("SetSlotState failed: %v", err)
+	}
+	if revoked == nil || revoked.LeaseID != lf.LeaseID {
+		t.Fatalf("expected the active claim to be revoked, got %+v", revoked)
+	}
+
+	// The former holder can no longer use or extend the lease
+	if _, err := e.Renew(ctx, lf); err == nil {
+		t.Error("expected Renew to fail after quarantine")
+	}
+	if _, err := e.ReadKey(ctx, lf, "SHOPIFY_API_KEY"); err == nil {
+		t.Error("expected ReadKey to fail after quarantine")
+	}
+
+	events, err := e.History(ctx, "testpool", "alpha", time.Time{})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if last := events[len(events)-1]; last.Kind != lockstore.EventRevoke {
+		t.Errorf("expected the quarantine to be recorded as a revocation, got %q", last.Kind)
+	}
+}
+
+func TestSetSlotStateUnknownSlot(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	if _, err := e.SetSlotState(ctx, "testpool", "gamma", lockstore.SlotDraining, ""); err == nil {
+		t.Error("expected error for unknown slot")
+	}
+}
+
+func TestReap(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	e.Cfg.Pools["testpool"] = pool
+
+	e.Identity = "holder-1"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !statuses[0].Expired || statuses[0].Claimed {
+		t.Error("expected slot 'alpha' to be reported as expired")
+	}
+
+	reaped, err := e.Reap(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Reap failed: %v", err)
+	}
+	if len(reaped) != 1 {
+		t.Fatalf("expected 1 reaped claim, got %d", len(reaped))
+	}
+	if reaped[0].SlotName != "alpha" || reaped[0].Holder != "holder-1" {
+		t.Errorf("expected alpha reaped from holder-1, got %q from %q", reaped[0].SlotName, reaped[0].Holder)
+	}
+
+	statuses, err = e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status after reap failed: %v", err)
+	}
+	if statuses[0].Expired || statuses[0].Claim != nil {
+		t.Error("expected slot 'alpha' to be free after reap")
+	}
+
+	// Nothing left to reap
+	reaped, err = e.Reap(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("second Reap failed: %v", err)
+	}
+	if len(reaped) != 0 {
+		t.Errorf("expected nothing to reap, got %d", len(reaped))
+	}
+}
+
+func TestHooks(t *testing.T) {
+	e, _, ss := testEngine()
+	ctx := context.Background()
+
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	pool := e.Cfg.Pools["testpool"]
+	pool.Hooks = config.HooksConfig{
+		PostClaim:   `echo "$CLAIMENV_HOOK $CLAIMENV_SLOT $CLAIMENV_HOLDER $SHOPIFY_API_KEY" >> ` + out,
+		PreRelease:  `echo "$CLAIMENV_HOOK $CLAIMENV_SLOT" >> ` + out,
+		PostRelease: `echo "$CLAIMENV_HOOK $CLAIMENV_SLOT" >> ` + out,
+	}
+	e.Cfg.Pools["testpool"] = pool
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read hook output: %v", err)
+	}
+	expected := "post_claim alpha test-holder key-alpha\npre_release alpha\npost_release alpha\n"
+	if string(data) != expected {
+		t.Errorf("unexpected hook output:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestPreReleaseHookFailureKeepsClaim(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Hooks.PreRelease = "exit 1"
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if err := e.ReleaseByHolder(ctx, "testpool"); err == nil {
+		t.Fatal("expected ReleaseByHolder to fail when pre_release hook fails")
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !statuses[0].Claimed {
+		t.Error("expected slot 'alpha' to remain claimed")
+	}
+}
+
+func TestPostClaimHookFailureReleasesClaim(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Hooks.PostClaim = "exit 1"
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err == nil {
+		t.Fatal("expected Claim to fail when post_claim hook fails")
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].Claimed {
+		t.Error("expected slot 'alpha' to be released after failed post_claim hook")
+	}
+}
+
+func TestRepeatClaimSkipsPostClaimHook(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// A failing hook must neither run for nor roll back the existing lease
+	pool := e.Cfg.Pools["testpool"]
+	pool.Hooks.PostClaim = "exit 1"
+	e.Cfg.Pools["testpool"] = pool
+
+	again, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("repeat Claim failed: %v", err)
+	}
+	if again.LeaseID != lf.LeaseID {
+		t.Errorf("expected the existing lease %s, got %s", lf.LeaseID, again.LeaseID)
+	}
+	if _, err := e.Renew(ctx, lf); err != nil {
+		t.Errorf("expected the existing lease to stay valid, got %v", err)
+	}
+}
+
+func TestReapRunsPostReleaseHook(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Hooks.PostRelease = `echo "$CLAIMENV_HOOK $CLAIMENV_SLOT $CLAIMENV_HOLDER" >> ` + out
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := e.Reap(ctx, "testpool"); err != nil {
+		t.Fatalf("Reap failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read hook output: %v", err)
+	}
+	if string(data) != "post_release alpha test-holder\n" {
+		t.Errorf("unexpected hook output: %q", data)
+	}
+}
+
+func TestReleaseResetsResettableKeys(t *testing.T) {
+	e, _, ss := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Resettable = []string{"APP_URL"}
+	e.Cfg.Pools["testpool"] = pool
+
+	ss.Seed("alpha-app-url-baseline", "https://baseline.example.com")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := e.WriteKey(ctx, lf, "APP_URL", "https://mr-423.preview.example.com"); err != nil {
+		t.Fatalf("WriteKey failed: %v", err)
+	}
+
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	lf, err = e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("re-Claim failed: %v", err)
+	}
+	val, err := e.ReadKey(ctx, lf, "APP_URL")
+	if err != nil {
+		t.Fatalf("ReadKey failed: %v", err)
+	}
+	if val != "https://baseline.example.com" {
+		t.Errorf("expected APP_URL reset to baseline, got %q", val)
+	}
+}
+
+func TestClaimQuarantinesSlotWithoutBaseline(t *testing.T) {
+	e, _, ss := testEngine()
+	e.Log = io.Discard
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Resettable = []string{"APP_URL"}
+	e.Cfg.Pools["testpool"] = pool
+
+	ss.Seed("beta-app-url-baseline", "https://baseline.example.com")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if lf.SlotName != "beta" {
+		t.Errorf("expected slot 'beta', got %q", lf.SlotName)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].State != lockstore.SlotQuarantined || statuses[0].Claimed {
+		t.Errorf("expected slot 'alpha' to be quarantined and free, got %+v", statuses[0])
+	}
+
+	// A key made resettable mid-lease has no baseline yet, which mustn't keep
+	// the slot claimed for good
+	pool.Resettable = append(pool.Resettable, "SHOPIFY_API_KEY")
+	e.Cfg.Pools["testpool"] = pool
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+}
+
+func TestClaimResetsExpiredSlot(t *testing.T) {
+	e, _, ss := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Resettable = []string{"APP_URL"}
+	pool.Slots = pool.Slots[:1]
+	e.Cfg.Pools["testpool"] = pool
+
+	ss.Seed("alpha-app-url-baseline", "https://baseline.example.com")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := e.WriteKey(ctx, lf, "APP_URL", "https://mr-423.preview.example.com"); err != nil {
+		t.Fatalf("WriteKey failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	other := *e
+	other.Identity = "other-holder"
+	lf, err = other.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim of the expired slot failed: %v", err)
+	}
+	val, err := other.ReadKey(ctx, lf, "APP_URL")
+	if err != nil {
+		t.Fatalf("ReadKey failed: %v", err)
+	}
+	if val != "https://baseline.example.com" {
+		t.Errorf("expected APP_URL reset to baseline, got %q", val)
+	}
+}
+
+// blockingSecretStore blocks reads of one secret until release is closed,
+// signalling reached when a read gets there.
+type blockingSecretStore struct {
+	*secretmem.Store
+	name    string
+	reached chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSecretStore) Read(ctx context.Context, name string) (string, error) {
+	if name == b.name {
+		close(b.reached)
+		<-b.release
+	}
+	return b.Store.Read(ctx, name)
+}
+
+func TestReapKeepsSlotUnclaimableUntilReset(t *testing.T) {
+	e, ls, ss := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Resettable = []string{"APP_URL"}
+	pool.Slots = pool.Slots[:1]
+	e.Cfg.Pools["testpool"] = pool
+
+	ss.Seed("alpha-app-url-baseline", "https://baseline.example.com")
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	blocking := &blockingSecretStore{
+		Store:   ss,
+		name:    "alpha-app-url-baseline",
+		reached: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	e.SecretStore = blocking
+
+	done := make(chan error)
+	go func() {
+		_, err := e.Reap(ctx, "testpool")
+		done <- err
+	}()
+	<-blocking.reached
+
+	// Mid-reset, the expired slot can't be handed to anyone else
+	if _, err := ls.Claim(ctx, "testpool", pool.SlotNames(), "other-holder", time.Hour, lockstore.ClaimOptions{}); !errors.Is(err, lockstore.ErrPoolExhausted) {
+		t.Errorf("expected ErrPoolExhausted while the slot is reset, got %v", err)
+	}
+
+	close(blocking.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Reap failed: %v", err)
+	}
+
+	if _, err := ls.Claim(ctx, "testpool", pool.SlotNames(), "other-holder", time.Hour, lockstore.ClaimOptions{}); err != nil {
+		t.Errorf("expected the reset slot to be claimable, got %v", err)
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Cooldown = 50 * time.Millisecond
+	e.Cfg.Pools["testpool"] = pool
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].CoolingUntil == nil {
+		t.Error("expected slot 'alpha' to be cooling")
+	}
+	if statuses[1].CoolingUntil != nil {
+		t.Error("expected never-claimed slot 'beta' not to be cooling")
+	}
+
+	// The cooling slot is skipped
+	lf, err = e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim during cooldown failed: %v", err)
+	}
+	if lf.SlotName != "beta" {
+		t.Errorf("expected cooling slot 'alpha' to be skipped, got %q", lf.SlotName)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	e.Identity = "holder-2"
+	lf, err = e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim after cooldown failed: %v", err)
+	}
+	if lf.SlotName != "alpha" {
+		t.Errorf("expected slot 'alpha' after cooldown, got %q", lf.SlotName)
+	}
+}
+
+func TestReapCooldownRunsFromExpiry(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Cooldown = 50 * time.Millisecond
+	e.Cfg.Pools["testpool"] = pool
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := e.Reap(ctx, "testpool"); err != nil {
+		t.Fatalf("Reap failed: %v", err)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	want := lf.ExpiresAt.Add(pool.Cooldown)
+	if statuses[0].CoolingUntil == nil || !statuses[0].CoolingUntil.Equal(want) {
+		t.Errorf("expected slot 'alpha' to cool until %v, got %v", want, statuses[0].CoolingUntil)
+	}
+}
+
+func TestRenewCappedByMaxLease(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.MaxLease = time.Hour + 10*time.Millisecond
+	e.Cfg.Pools["testpool"] = pool
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// Renewing by the full TTL would now overshoot max_lease
+	time.Sleep(20 * time.Millisecond)
+
+	renewed, err := e.Renew(ctx, lf)
+	if err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if limit := lf.ClaimedAt.Add(pool.MaxLease); !renewed.ExpiresAt.Equal(limit) {
+		t.Errorf("expected expiry capped at %s, got %s", limit, renewed.ExpiresAt)
+	}
+
+	_, err = e.Renew(ctx, renewed)
+	if !errors.Is(err, lockstore.ErrMaxLeaseExceeded) {
+		t.Errorf("expected ErrMaxLeaseExceeded, got %v", err)
+	}
+}
+
+func TestClaimQuota(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Quotas = []config.QuotaConfig{
+		{Name: "per-mr", Match: `^mr-(\d+)-`, Max: 1},
+	}
+	e.Cfg.Pools["testpool"] = pool
+
+	e.Identity = "mr-1-job-1"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("first claim failed: %v", err)
+	}
+
+	// Idempotent re-claim is not limited by the quota
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("re-claim by same holder failed: %v", err)
+	}
+
+	e.Identity = "mr-1-job-2"
+	_, err := e.Claim(ctx, "testpool", nil)
+	if !errors.Is(err, lockstore.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded for second job of the same MR, got %v", err)
+	}
+
+	e.Identity = "mr-2-job-1"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Errorf("claim for a different MR failed: %v", err)
+	}
+}
+
+func TestReservationBlocksOtherClaims(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	// Reserve a slot starting in 30 minutes, within the pool's 1h TTL
+	r, err := e.Reserve(ctx, "testpool", "nightly", time.Now().Add(30*time.Minute), time.Hour)
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if r.SlotName != "alpha" {
+		t.Errorf("expected slot 'alpha' to be reserved, got %q", r.SlotName)
+	}
+
+	e.Identity = "holder-1"
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if lf.SlotName != "beta" {
+		t.Errorf("expected reserved slot 'alpha' to be skipped, got %q", lf.SlotName)
+	}
+
+	e.Identity = "holder-2"
+	if _, err := e.Claim(ctx, "testpool", nil); err != lockstore.ErrPoolExhausted {
+		t.Errorf("expected ErrPoolExhausted, got %v", err)
+	}
+
+	// No slot left for an overlapping reservation
+	if _, err := e.Reserve(ctx, "testpool", "demo", time.Now().Add(45*time.Minute), time.Hour); err != lockstore.ErrReservationConflict {
+		t.Errorf("expected ErrReservationConflict, got %v", err)
+	}
+
+	if err := e.CancelReservation(ctx, "testpool", r.ID); err != nil {
+		t.Fatalf("CancelReservation failed: %v", err)
+	}
+
+	lf, err = e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim after cancel failed: %v", err)
+	}
+	if lf.SlotName != "alpha" {
+		t.Errorf("expected slot 'alpha' after cancel, got %q", lf.SlotName)
+	}
+}
+
+func TestReservationGuaranteesReserverClaim(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	// Reserve the second slot while the first is taken
+	e.Identity = "holder-1"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	r, err := e.Reserve(ctx, "testpool", "nightly", time.Now(), time.Hour)
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if r.SlotName != "beta" {
+		t.Fatalf("expected slot 'beta' to be reserved, got %q", r.SlotName)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(statuses[1].Reservations) != 1 {
+		t.Errorf("expected slot 'beta' to show 1 reservation, got %d", len(statuses[1].Reservations))
+	}
+
+	e.Identity = "nightly"
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("reserver Claim failed: %v", err)
+	}
+	if lf.SlotName != "beta" {
+		t.Errorf("expected reserver to get slot 'beta', got %q", lf.SlotName)
+	}
+}
+
+func TestRenewCappedByReservation(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Slots = pool.Slots[:1]
+	e.Cfg.Pools["testpool"] = pool
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// The reservation starts right when the current lease ends
+	if _, err := e.Reserve(ctx, "testpool", "nightly", lf.ExpiresAt, time.Hour); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+
+	if _, err := e.Renew(ctx, lf); !errors.Is(err, lockstore.ErrSlotReserved) {
+		t.Errorf("expected ErrSlotReserved, got %v", err)
+	}
+}
+
+func TestClaimAnnotations(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	annotations := map[string]string{"mr": "https://gitlab.example.com/mr/423", "branch": "feature-x"}
+	lf, err := e.Claim(ctx, "testpool", annotations)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if lf.Annotations["branch"] != "feature-x" {
+		t.Errorf("expected lease annotations, got %v", lf.Annotations)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].Claim == nil || statuses[0].Claim.Annotations["mr"] != "https://gitlab.example.com/mr/423" {
+		t.Errorf("expected annotations in status, got %+v", statuses[0].Claim)
+	}
+
+	renewed, err := e.Renew(ctx, lf)
+	if err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if renewed.Annotations["branch"] != "feature-x" {
+		t.Error("expected annotations to be preserved after renew")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+	start := time.Now()
+
+	lf, err := e.Claim(ctx, "testpool", map[string]string{"mr": "423"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if lf, err = e.Renew(ctx, lf); err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	e.Identity = "holder-2"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("second Claim failed: %v", err)
+	}
+	claim, err := e.Revoke(ctx, "testpool", "alpha")
+	if err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if claim.Holder != "holder-2" {
+		t.Errorf("expected revoked claim of holder-2, got %q", claim.Holder)
+	}
+
+	events, err := e.History(ctx, "testpool", "alpha", start)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+
+	expected := []lockstore.EventKind{
+		lockstore.EventClaim, lockstore.EventRenew, lockstore.EventRelease,
+		lockstore.EventClaim, lockstore.EventRevoke,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, kind := range expected {
+		if events[i].Kind != kind {
+			t.Errorf("event %d: expected %q, got %q", i, kind, events[i].Kind)
+		}
+	}
+	if events[0].Holder != "test-holder" || events[0].Annotations["mr"] != "423" {
+		t.Errorf("expected first claim by test-holder with annotations, got %+v", events[0])
+	}
+
+	// Nothing recorded for beta, and nothing after now
+	if events, _ := e.History(ctx, "testpool", "beta", start); len(events) != 0 {
+		t.Errorf("expected no events for slot 'beta', got %d", len(events))
+	}
+	if events, _ := e.History(ctx, "testpool", "", time.Now().Add(time.Minute)); len(events) != 0 {
+		t.Errorf("expected no events in the future, got %d", len(events))
+	}
+}
+
+func TestHistoryRecordsExpiry(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+	start := time.Now()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Slots = pool.Slots[:1]
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Claiming reaps the lapsed lease first, recording its expiry
+	e.Identity = "holder-2"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("second Claim failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// So does reaping it
+	if _, err := e.Reap(ctx, "testpool"); err != nil {
+		t.Fatalf("Reap failed: %v", err)
+	}
+
+	events, err := e.History(ctx, "testpool", "", start)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+
+	expected := []lockstore.EventKind{
+		lockstore.EventClaim, lockstore.EventExpire, lockstore.EventClaim, lockstore.EventExpire,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, kind := range expected {
+		if events[i].Kind != kind {
+			t.Errorf("event %d: expected %q, got %q", i, kind, events[i].Kind)
+		}
+	}
+}
+
+func TestAuditSecretAccess(t *testing.T) {
+	e, ls, ss := testEngine()
+	ctx := context.Background()
+	e.Audit = audit.NewBackendSink(ls)
+
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+	ss.Seed("alpha-app-url", "https://alpha.example.com")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if _, err := e.ReadKey(ctx, lf, "SHOPIFY_API_KEY"); err != nil {
+		t.Fatalf("ReadKey failed: %v", err)
+	}
+	if _, err := e.ReadAll(ctx, lf); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if err := e.WriteKey(ctx, lf, "APP_URL", "https://mr-423.example.com"); err != nil {
+		t.Fatalf("WriteKey failed: %v", err)
+	}
+
+	events := ls.AuditLog()
+	if len(events) != 4 {
+		t.Fatalf("expected 4 audit events, got %d", len(events))
+	}
+
+	first := events[0]
+	if first.Action != audit.ActionRead || first.Key != "SHOPIFY_API_KEY" || first.Slot != "alpha" ||
+		first.LeaseID != lf.LeaseID || first.Holder != "test-holder" {
+		t.Errorf("unexpected first audit event: %+v", first)
+	}
+	if last := events[3]; last.Action != audit.ActionWrite || last.Key != "APP_URL" {
+		t.Errorf("unexpected last audit event: %+v", last)
+	}
+}
+
+func TestAuditHookAndResetAccess(t *testing.T) {
+	e, ls, ss := testEngine()
+	e.Log = io.Discard
+	ctx := context.Background()
+	e.Audit = audit.NewBackendSink(ls)
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.Keys = []string{"APP_URL"}
+	pool.Resettable = []string{"APP_URL"}
+	pool.Hooks.PostClaim = "true"
+	e.Cfg.Pools["testpool"] = pool
+
+	ss.Seed("alpha-app-url-baseline", "https://baseline.example.com")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	want := []struct {
+		action audit.Action
+		actor  string
+	}{
+		{audit.ActionRead, "reset"},           // baseline check on claim
+		{audit.ActionRead, "hook:post_claim"}, // hook environment
+		{audit.ActionRead, "reset"},           // baseline read on release
+		{audit.ActionWrite, "reset"},          // key restored
+	}
+	events := ls.AuditLog()
+	if len(events) != len(want) {
+		t.Fatalf("expected %d audit events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		ev := events[i]
+		if ev.Action != w.action || ev.Actor != w.actor || ev.Key != "APP_URL" || ev.LeaseID != lf.LeaseID {
+			t.Errorf("event %d: expected %s by %s, got %+v", i, w.action, w.actor, ev)
+		}
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Emit(context.Context, audit.Event) error { return errors.New("sink down") }
+func (failingSink) Close() error                            { return nil }
+
+func TestAuditFailureRefusesAccess(t *testing.T) {
+	e, _, ss := testEngine()
+	ctx := context.Background()
+	e.Audit = failingSink{}
+
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if val, err := e.ReadKey(ctx, lf, "SHOPIFY_API_KEY"); err == nil || val != "" {
+		t.Errorf("expected ReadKey to be refused, got %q, %v", val, err)
+	}
+	if err := e.WriteKey(ctx, lf, "APP_URL", "x"); err == nil {
+		t.Error("expected WriteKey to be refused")
+	}
+	if _, err := ss.Read(ctx, "alpha-app-url"); err == nil {
+		t.Error("expected refused write not to reach the secret store")
+	}
+}
+
+func TestClaimSpanCarriesPoolSlotAndHolder(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	e, _, _ := testEngine()
+	ctx := context.Background()
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "engine.Claim" {
+			span = s
+		}
+	}
+	if span == nil {
+		t.Fatal("expected an engine.Claim span")
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+		if kv.Value.Emit() == lf.LeaseID {
+			t.Errorf("span attribute %s leaks the lease ID", kv.Key)
+		}
+	}
+	if attrs["claimenv.pool"] != "testpool" || attrs["claimenv.slot"] != "alpha" || attrs["claimenv.holder"] != "test-holder" {
+		t.Errorf("unexpected span attributes: %v", attrs)
+	}
+}
+
+type recordingNotifier struct {
+	events []notify.Event
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, ev notify.Event) error {
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func TestNotifications(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+	n := &recordingNotifier{}
+	e.Notifier = n
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.ExpiryWarning = 2 * time.Hour
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if _, err := e.SetSlotState(ctx, "testpool", "beta", lockstore.SlotQuarantined, "app suspended"); err != nil {
+		t.Fatalf("SetSlotState failed: %v", err)
+	}
+
+	e.Identity = "other-holder"
+	if _, err := e.Claim(ctx, "testpool", nil); !errors.Is(err, lockstore.ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+
+	// The only lease expires within the two hour warning
+	expiring, err := e.WarnExpiring(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("WarnExpiring failed: %v", err)
+	}
+	if len(expiring) != 1 || expiring[0].Holder != "test-holder" {
+		t.Errorf("expected test-holder's lease to be expiring, got %+v", expiring)
+	}
+
+	var types []notify.EventType
+	for _, ev := range n.events {
+		types = append(types, ev.Type)
+	}
+	want := []notify.EventType{notify.SlotQuarantined, notify.PoolExhausted, notify.LeaseExpiring}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], types[i])
+		}
+	}
+	if n.events[1].Holder != "other-holder" {
+		t.Errorf("expected exhaustion to name the waiting holder, got %q", n.events[1].Holder)
+	}
+}
+
+func TestClaimNotifiesExpiredTakeover(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx := context.Background()
+	n := &recordingNotifier{}
+	e.Notifier = n
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 10 * time.Millisecond
+	pool.Slots = pool.Slots[:1]
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	e.Identity = "other-holder"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim of the expired slot failed: %v", err)
+	}
+
+	if len(n.events) != 1 || n.events[0].Type != notify.LeaseExpired {
+		t.Fatalf("expected a single lease_expired event, got %+v", n.events)
+	}
+	if n.events[0].Slot != "alpha" || n.events[0].Holder != "test-holder" {
+		t.Errorf("expected the event to name alpha and its lapsed holder, got %+v", n.events[0])
+	}
+}
+
+var errStopWatching = errors.New("stop watching")
+
+func TestWatchPollsStoresWithoutWatcher(t *testing.T) {
+	e, _, _ := testEngine()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var phases []string
+	err := e.Watch(ctx, "testpool", 10*time.Millisecond, func(statuses []lockstore.SlotStatus) error {
+		phases = append(phases, statuses[0].Phase())
+		if statuses[0].Claimed {
+			return errStopWatching
+		}
+		if len(phases) == 1 {
+			if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+				t.Fatalf("Claim failed: %v", err)
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, errStopWatching) {
+		t.Fatalf("expected Watch to return fn's error, got %v", err)
+	}
+	if phases[0] != "free" || phases[len(phases)-1] != "claimed" {
+		t.Errorf("expected alpha to go from free to claimed, got %v", phases)
+	}
+}
+
+// pushStore is a lock store that pushes a single snapshot to watchers.
+type pushStore struct {
+	*lockmem.Store
+}
+
+func (p pushStore) Watch(ctx context.Context, pool string, slotNames []string, fn func([]lockstore.SlotStatus) error) error {
+	statuses, err := p.Status(ctx, pool, slotNames)
+	if err != nil {
+		return err
+	}
+	if err := fn(statuses); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestWatchRefreshesExpiryWithoutStoreChanges(t *testing.T) {
+	e, ls, _ := testEngine()
+	e.LockStore = pushStore{ls}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool := e.Cfg.Pools["testpool"]
+	pool.TTL = 50 * time.Millisecond
+	e.Cfg.Pools["testpool"] = pool
+
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// The store only pushes once, so the expiry must come from the engine
+	var phases []string
+	err := e.Watch(ctx, "testpool", time.Hour, func(statuses []lockstore.SlotStatus) error {
+		phases = append(phases, statuses[0].Phase())
+		if statuses[0].Expired {
+			return errStopWatching
+		}
+		return nil
+	})
+	if !errors.Is(err, errStopWatching) {
+		t.Fatalf("expected Watch to return fn's error, got %v", err)
+	}
+	if len(phases) != 2 || phases[0] != "claimed" || phases[1] != "expired" {
+		t.Errorf("expected alpha to go from claimed to expired, got %v", phases)
+	}
+}
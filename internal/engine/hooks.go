@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/Kashuab/claimenv/internal/audit"
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/tracing"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Hook names, exposed to hook commands as CLAIMENV_HOOK.
+const (
+	hookPostClaim   = "post_claim"
+	hookPreRelease  = "pre_release"
+	hookPostRelease = "post_release"
+)
+
+// runHook runs a pool hook command for the given claim. The command sees the
+// pool, slot, holder and lease ID as CLAIMENV_* variables, plus every secret
+// value of the slot under its env var key and, when tracing, TRACEPARENT. Each
+// secret read is audited with the hook as its actor.
+// Hook output goes to the engine's log (stderr by default) so it never mixes
+// with values printed on stdout.
+func (e *Engine) runHook(ctx context.Context, pool *config.PoolConfig, name, command string, claim *lockstore.Claim) (err error) {
+	if command == "" {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "hook "+name, trace.WithAttributes(
+		tracing.PoolKey.String(claim.Pool),
+		tracing.SlotKey.String(claim.SlotName),
+		tracing.HolderKey.String(claim.Holder),
+	))
+	defer tracing.End(span, &err)
+
+	env := append(os.Environ(),
+		"CLAIMENV_HOOK="+name,
+		"CLAIMENV_POOL="+claim.Pool,
+		"CLAIMENV_SLOT="+claim.SlotName,
+		"CLAIMENV_HOLDER="+claim.Holder,
+		"CLAIMENV_LEASE_ID="+claim.LeaseID,
+	)
+
+	for key, secretName := range pool.SecretsForSlot(claim.SlotName) {
+		if err := e.auditClaim(ctx, audit.ActionRead, claim, key, "hook:"+name); err != nil {
+			return fmt.Errorf("%s hook: %w", name, err)
+		}
+		val, err := e.SecretStore.Read(ctx, secretName)
+		if err != nil {
+			if errors.Is(err, secretstore.ErrSecretNotFound) {
+				continue
+			}
+			return fmt.Errorf("%s hook: failed to read secret for key %q: %w", name, key, err)
+		}
+		env = append(env, key+"="+val)
+	}
+
+	// Hooks that are traced themselves continue the claimenv trace
+	env = append(env, tracing.Environ(ctx)...)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = env
+	cmd.Stdout = e.log()
+	cmd.Stderr = e.log()
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook for slot %q failed: %w", name, claim.SlotName, err)
+	}
+	return nil
+}
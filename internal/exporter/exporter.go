@@ -0,0 +1,154 @@
+// Package exporter publishes pool utilization as Prometheus metrics by polling
+// Engine.Status.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// phases are the slot phases reported by the claimenv_pool_slots gauge. Every
+// phase is set on each poll, so a phase with no slots reads 0 rather than
+// disappearing.
+var phases = []string{"free", "claimed", "expired", "cooling", "draining", "quarantined"}
+
+// Exporter polls the engine for the status of every configured pool and keeps
+// the metrics registered with it up to date.
+type Exporter struct {
+	eng *engine.Engine
+
+	slots     *prometheus.GaugeVec
+	leaseAge  *prometheus.GaugeVec
+	exhausted *prometheus.CounterVec
+	wait      *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+
+	// exhaustedSince records when each currently exhausted pool ran out of
+	// claimable slots.
+	exhaustedSince map[string]time.Time
+}
+
+// New creates an exporter for eng and registers its metrics with reg.
+func New(eng *engine.Engine, reg prometheus.Registerer) (*Exporter, error) {
+	x := &Exporter{
+		eng: eng,
+		slots: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "claimenv_pool_slots",
+			Help: "Number of slots in each pool by state.",
+		}, []string{"pool", "state"}),
+		leaseAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "claimenv_lease_age_seconds",
+			Help: "Seconds since the active lease on each claimed slot was taken.",
+		}, []string{"pool", "slot"}),
+		exhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "claimenv_pool_exhausted_total",
+			Help: "Number of times a pool ran out of claimable slots.",
+		}, []string{"pool"}),
+		wait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "claimenv_pool_claim_wait_seconds",
+			Help:    "How long a pool stayed exhausted before a slot became claimable again, i.e. the longest a new claim had to wait.",
+			Buckets: []float64{30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400},
+		}, []string{"pool"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "claimenv_scrape_errors_total",
+			Help: "Number of failed status polls per pool.",
+		}, []string{"pool"}),
+		exhaustedSince: make(map[string]time.Time),
+	}
+
+	for _, c := range []prometheus.Collector{x.slots, x.leaseAge, x.exhausted, x.wait, x.errors} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+
+	return x, nil
+}
+
+// Poll reads the status of every configured pool once and updates the
+// metrics. A pool whose status can't be read keeps its previous values and
+// bumps claimenv_scrape_errors_total; the first such error is returned.
+func (x *Exporter) Poll(ctx context.Context) error {
+	pools := make([]string, 0, len(x.eng.Cfg.Pools))
+	for name := range x.eng.Cfg.Pools {
+		pools = append(pools, name)
+	}
+	sort.Strings(pools)
+
+	var firstErr error
+	for _, pool := range pools {
+		statuses, err := x.eng.Status(ctx, pool)
+		if err != nil {
+			x.errors.WithLabelValues(pool).Inc()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to get status for pool %q: %w", pool, err)
+			}
+			continue
+		}
+		x.observe(pool, statuses, time.Now())
+	}
+
+	return firstErr
+}
+
+func (x *Exporter) observe(pool string, statuses []lockstore.SlotStatus, now time.Time) {
+	counts := make(map[string]int, len(phases))
+	claimable := 0
+
+	x.leaseAge.DeletePartialMatch(prometheus.Labels{"pool": pool})
+
+	for i := range statuses {
+		s := &statuses[i]
+		phase := s.Phase()
+		counts[phase]++
+
+		switch phase {
+		case "free", "expired":
+			claimable++
+		case "claimed":
+			if s.Claim != nil {
+				x.leaseAge.WithLabelValues(pool, s.SlotName).Set(now.Sub(s.Claim.ClaimedAt).Seconds())
+			}
+		}
+	}
+
+	for _, phase := range phases {
+		x.slots.WithLabelValues(pool, phase).Set(float64(counts[phase]))
+	}
+
+	since, wasExhausted := x.exhaustedSince[pool]
+	switch {
+	case claimable == 0 && !wasExhausted:
+		x.exhausted.WithLabelValues(pool).Inc()
+		x.exhaustedSince[pool] = now
+	case claimable > 0 && wasExhausted:
+		x.wait.WithLabelValues(pool).Observe(now.Sub(since).Seconds())
+		delete(x.exhaustedSince, pool)
+	}
+}
+
+// Run polls every interval until ctx is cancelled. Poll errors are reported
+// on stderr and don't stop the exporter.
+func (x *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := x.Poll(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "exporter: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package exporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/internal/exporter"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPollTracksUtilizationAndExhaustion(t *testing.T) {
+	e := &engine.Engine{
+		Cfg: &config.Config{
+			Pools: map[string]config.PoolConfig{
+				"testpool": {
+					Keys:  []string{"SHOPIFY_API_KEY"},
+					Slots: []config.SlotConfig{{Name: "alpha"}, {Name: "beta"}},
+					TTL:   time.Hour,
+				},
+			},
+		},
+		LockStore:   lockmem.New(),
+		SecretStore: secretmem.New(),
+		Identity:    "test-holder",
+	}
+	ctx := context.Background()
+
+	reg := prometheus.NewRegistry()
+	x, err := exporter.New(e, reg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Take both slots so the pool is exhausted
+	first, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	e.Identity = "other-holder"
+	if _, err := e.Claim(ctx, "testpool", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if err := x.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	// A second poll while still exhausted must not count a new exhaustion
+	if err := x.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	values := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			labels := ""
+			for _, l := range m.GetLabel() {
+				labels += l.GetName() + "=" + l.GetValue() + ","
+			}
+			switch {
+			case m.GetGauge() != nil:
+				values[f.GetName()+"{"+labels+"}"] = m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				values[f.GetName()+"{"+labels+"}"] = m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	if got := values["claimenv_pool_slots{pool=testpool,state=claimed,}"]; got != 2 {
+		t.Errorf("expected 2 claimed slots, got %v", got)
+	}
+	if got := values["claimenv_pool_slots{pool=testpool,state=free,}"]; got != 0 {
+		t.Errorf("expected 0 free slots, got %v", got)
+	}
+	if got := values["claimenv_pool_exhausted_total{pool=testpool,}"]; got != 1 {
+		t.Errorf("expected 1 exhaustion, got %v", got)
+	}
+	if _, ok := values["claimenv_lease_age_seconds{pool=testpool,slot=alpha,}"]; !ok {
+		t.Error("expected a lease age for slot alpha")
+	}
+
+	// Freeing a slot ends the exhaustion and drops the slot's lease age
+	e.Identity = "test-holder"
+	if err := e.Release(ctx, first); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := x.Poll(ctx); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(reg, "claimenv_pool_claim_wait_seconds"); got != 1 {
+		t.Errorf("expected a claim wait observation, got %d series", got)
+	}
+	if got := testutil.CollectAndCount(reg, "claimenv_lease_age_seconds"); got != 1 {
+		t.Errorf("expected 1 lease age series after release, got %d", got)
+	}
+}
@@ -0,0 +1,144 @@
+// Package notify delivers pool events such as exhaustion or lapsed leases to
+// webhooks, so people hear about leaked slots before a pipeline fails.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// EventType identifies what happened. The values match the event names
+// accepted in the notifications section of the config.
+type EventType string
+
+const (
+	PoolExhausted   EventType = "pool_exhausted"
+	LeaseExpired    EventType = "lease_expired"
+	LeaseExpiring   EventType = "lease_expiring"
+	SlotQuarantined EventType = "slot_quarantined"
+)
+
+// Event is the JSON payload POSTed for a notification. Slot, Holder and
+// ExpiresAt are empty when they don't apply to the event type.
+type Event struct {
+	Type      EventType  `json:"type"`
+	Time      time.Time  `json:"time"`
+	Pool      string     `json:"pool"`
+	Slot      string     `json:"slot,omitempty"`
+	Holder    string     `json:"holder,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+}
+
+// Message returns a one-line human readable description of the event.
+func (e Event) Message() string {
+	switch e.Type {
+	case PoolExhausted:
+		return fmt.Sprintf("Pool %q is exhausted: %s could not claim a slot", e.Pool, e.Holder)
+	case LeaseExpired:
+		return fmt.Sprintf("Lease on slot %q in pool %q expired without release (holder: %s)", e.Slot, e.Pool, e.Holder)
+	case LeaseExpiring:
+		return fmt.Sprintf("Lease on slot %q in pool %q expires at %s (holder: %s)",
+			e.Slot, e.Pool, e.ExpiresAt.Format("2006-01-02 15:04:05"), e.Holder)
+	case SlotQuarantined:
+		if e.Reason != "" {
+			return fmt.Sprintf("Slot %q in pool %q was quarantined: %s", e.Slot, e.Pool, e.Reason)
+		}
+		return fmt.Sprintf("Slot %q in pool %q was quarantined", e.Slot, e.Pool)
+	default:
+		return fmt.Sprintf("%s in pool %q", e.Type, e.Pool)
+	}
+}
+
+// Notifier delivers events somewhere people will see them.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// Webhook POSTs events to a URL, either as the Event JSON or, with the
+// "slack" format, as a Slack-compatible {"text": ...} message.
+type Webhook struct {
+	URL    string
+	Format string
+
+	// Events limits which event types are sent. Empty sends every event.
+	Events []EventType
+
+	// Client defaults to an http.Client with a 10 second timeout.
+	Client *http.Client
+}
+
+func (w *Webhook) wants(t EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) Notify(ctx context.Context, ev Event) error {
+	if !w.wants(ev.Type) {
+		return nil
+	}
+
+	var payload interface{} = ev
+	if w.Format == "slack" {
+		payload = map[string]string{"text": ev.Message()}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Webhook URLs embed credentials, so keep them out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send %s notification: %w", ev.Type, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send %s notification: webhook returned %s", ev.Type, resp.Status)
+	}
+	return nil
+}
+
+// Multi sends every event to each of its notifiers, attempting all of them
+// even if some fail.
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, ev Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
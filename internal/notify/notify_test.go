@@ -0,0 +1,62 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kashuab/claimenv/internal/notify"
+)
+
+func TestWebhookPostsJSONAndSlackPayloads(t *testing.T) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected JSON content type, got %q", ct)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		bodies = append(bodies, body)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	ev := notify.Event{Type: notify.SlotQuarantined, Pool: "onboard", Slot: "app-alpha", Reason: "app suspended"}
+
+	n := notify.Multi{
+		&notify.Webhook{URL: srv.URL},
+		&notify.Webhook{URL: srv.URL, Format: "slack"},
+		&notify.Webhook{URL: srv.URL, Events: []notify.EventType{notify.PoolExhausted}},
+	}
+	if err := n.Notify(ctx, ev); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	// The third webhook isn't subscribed to quarantines
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if bodies[0]["type"] != "slot_quarantined" || bodies[0]["slot"] != "app-alpha" || bodies[0]["reason"] != "app suspended" {
+		t.Errorf("unexpected JSON payload: %v", bodies[0])
+	}
+	if text, _ := bodies[1]["text"].(string); !strings.Contains(text, "app-alpha") || !strings.Contains(text, "app suspended") {
+		t.Errorf("unexpected Slack payload: %v", bodies[1])
+	}
+}
+
+func TestWebhookReportsFailureStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w := &notify.Webhook{URL: srv.URL}
+	if err := w.Notify(context.Background(), notify.Event{Type: notify.PoolExhausted, Pool: "onboard"}); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+}
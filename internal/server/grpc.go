@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	claimenvv1 "github.com/Kashuab/claimenv/pkg/proto/claimenv/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo attached to failed
+// gRPC calls. The reason is the same error code the HTTP API returns.
+const ErrorDomain = "claimenv"
+
+const defaultWatchInterval = 2 * time.Second
+
+// GRPC returns a gRPC server serving the claimenv.v1.LockService for the
+// server's engine, authenticated with the same bearer tokens as the HTTP API.
+func (s *Server) GRPC(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	}, opts...)
+
+	g := grpc.NewServer(opts...)
+	claimenvv1.RegisterLockServiceServer(g, &lockService{s: s})
+	return g
+}
+
+// adminMethods are the gRPC methods only admin tokens may call.
+var adminMethods = map[string]bool{
+	claimenvv1.LockService_Revoke_FullMethodName:            true,
+	claimenvv1.LockService_Reap_FullMethodName:              true,
+	claimenvv1.LockService_Reserve_FullMethodName:           true,
+	claimenvv1.LockService_CancelReservation_FullMethodName: true,
+	claimenvv1.LockService_SetSlotState_FullMethodName:      true,
+}
+
+// authorizeGRPC checks the bearer token in the call's metadata, and that it
+// may call method, and returns a context carrying the token.
+func (s *Server) authorizeGRPC(ctx context.Context, method string) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+
+	token, ok := s.authorize(header)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	if adminMethods[method] && !token.Admin {
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires an admin token", method)
+	}
+	return context.WithValue(ctx, tokenKey{}, token), nil
+}
+
+// checkPool rejects requests for pools missing from the server's config.
+func (s *Server) checkPool(req interface{}) error {
+	r, ok := req.(interface{ GetPool() string })
+	if !ok {
+		return nil
+	}
+	if _, ok := s.eng.Cfg.Pools[r.GetPool()]; !ok {
+		return status.Errorf(codes.NotFound, "pool %q not found in config", r.GetPool())
+	}
+	return nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorizeGRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPool(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorizeGRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream replaces a stream's context with the authorized one.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authedStream) Context() context.Context { return a.ctx }
+
+// grpcEngineFor is engineFor for gRPC calls, reading the caller's identity
+// from the call's metadata.
+func (s *Server) grpcEngineFor(ctx context.Context) *engine.Engine {
+	return s.engineAs(ctx, grpcIdentity(ctx))
+}
+
+// grpcHolderEngine is holderEngine for gRPC calls, acting as holder or else
+// as the identity in the call's metadata.
+func (s *Server) grpcHolderEngine(ctx context.Context, holder string) (*engine.Engine, error) {
+	if holder == "" {
+		holder = grpcIdentity(ctx)
+	}
+	e, err := s.holderEngine(ctx, holder)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return e, nil
+}
+
+// grpcIdentity returns the identity in the call's metadata, if any.
+func grpcIdentity(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(api.IdentityHeader); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// grpcLease rebuilds a lease, which must be held by a holder the caller's
+// token may act as.
+func (s *Server) grpcLease(ctx context.Context, pool, leaseID string) (*lease.LeaseFile, error) {
+	lf, err := s.eng.Lease(ctx, pool, leaseID)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := checkLeaseHolder(ctx, lf.Holder); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// checkLeaseHolder refuses the lease of a holder the caller's token may not
+// act as.
+func checkLeaseHolder(ctx context.Context, holder string) error {
+	if token, _ := ctx.Value(tokenKey{}).(Token); !token.mayActAs(holder) {
+		return status.Errorf(codes.PermissionDenied, "token %q may not use the leases of %q", token.Name, holder)
+	}
+	return nil
+}
+
+// grpcError converts an engine error to a gRPC status, tagged with the error's
+// code so clients can tell e.g. an exhausted pool from a missing lease.
+func grpcError(err error) error {
+	_, reason := api.StatusFor(err)
+
+	code := codes.Internal
+	switch reason {
+	case "pool_exhausted", "quota_exceeded", "reservation_conflict":
+		code = codes.ResourceExhausted
+	case "lease_not_found", "reservation_not_found", "secret_not_found":
+		code = codes.NotFound
+	case "lease_expired", "max_lease_exceeded", "slot_reserved":
+		code = codes.FailedPrecondition
+	}
+
+	st := status.New(code, err.Error())
+	if reason != "" {
+		if detailed, derr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}); derr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// lockService implements claimenvv1.LockServiceServer on top of the engine.
+type lockService struct {
+	claimenvv1.UnimplementedLockServiceServer
+	s *Server
+}
+
+func (l *lockService) Claim(ctx context.Context, req *claimenvv1.ClaimRequest) (*claimenvv1.Claim, error) {
+	if req.GetHolder() == "" {
+		return nil, status.Error(codes.InvalidArgument, "holder is required")
+	}
+
+	e, err := l.s.grpcHolderEngine(ctx, req.GetHolder())
+	if err != nil {
+		return nil, err
+	}
+	lf, err := e.Claim(ctx, req.GetPool(), req.GetAnnotations())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return leaseToProto(lf), nil
+}
+
+func (l *lockService) Release(ctx context.Context, req *claimenvv1.ReleaseRequest) (*claimenvv1.ReleaseResponse, error) {
+	lf, err := l.s.grpcLease(ctx, req.GetPool(), req.GetLeaseId())
+	if err != nil {
+		return nil, err
+	}
+	if err := l.s.grpcEngineFor(ctx).Release(ctx, lf); err != nil {
+		return nil, grpcError(err)
+	}
+	return &claimenvv1.ReleaseResponse{}, nil
+}
+
+func (l *lockService) Revoke(ctx context.Context, req *claimenvv1.RevokeRequest) (*claimenvv1.Claim, error) {
+	claim, err := l.s.grpcEngineFor(ctx).Revoke(ctx, req.GetPool(), req.GetSlotName())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return claimToProto(claim), nil
+}
+
+func (l *lockService) ReleaseByHolder(ctx context.Context, req *claimenvv1.ReleaseByHolderRequest) (*claimenvv1.ReleaseByHolderResponse, error) {
+	if req.GetHolder() == "" {
+		return nil, status.Error(codes.InvalidArgument, "holder is required")
+	}
+	e, err := l.s.grpcHolderEngine(ctx, req.GetHolder())
+	if err != nil {
+		return nil, err
+	}
+	if err := e.ReleaseByHolder(ctx, req.GetPool()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &claimenvv1.ReleaseByHolderResponse{}, nil
+}
+
+func (l *lockService) FindByHolder(ctx context.Context, req *claimenvv1.FindByHolderRequest) (*claimenvv1.Claim, error) {
+	if req.GetHolder() == "" {
+		return nil, status.Error(codes.InvalidArgument, "holder is required")
+	}
+	e, err := l.s.grpcHolderEngine(ctx, req.GetHolder())
+	if err != nil {
+		return nil, err
+	}
+	claim, err := e.LockStore.FindByHolder(ctx, req.GetPool(), req.GetHolder())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return claimToProto(claim), nil
+}
+
+func (l *lockService) Renew(ctx context.Context, req *claimenvv1.RenewRequest) (*claimenvv1.Claim, error) {
+	lf, err := l.s.grpcLease(ctx, req.GetPool(), req.GetLeaseId())
+	if err != nil {
+		return nil, err
+	}
+	renewed, err := l.s.grpcEngineFor(ctx).Renew(ctx, lf)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return leaseToProto(renewed), nil
+}
+
+func (l *lockService) Reap(ctx context.Context, req *claimenvv1.ReapRequest) (*claimenvv1.ReapResponse, error) {
+	reaped, err := l.s.grpcEngineFor(ctx).Reap(ctx, req.GetPool())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &claimenvv1.ReapResponse{}
+	for i := range reaped {
+		resp.Claims = append(resp.Claims, claimToProto(&reaped[i]))
+	}
+	return resp, nil
+}
+
+func (l *lockService) Reserve(ctx context.Context, req *claimenvv1.ReserveRequest) (*claimenvv1.Reservation, error) {
+	if req.GetHolder() == "" {
+		return nil, status.Error(codes.InvalidArgument, "holder is required")
+	}
+	from := time.Now()
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+
+	r, err := l.s.grpcEngineFor(ctx).Reserve(ctx, req.GetPool(), req.GetHolder(), from, req.GetDuration().AsDuration())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return reservationToProto(r), nil
+}
+
+func (l *lockService) CancelReservation(ctx context.Context, req *claimenvv1.CancelReservationRequest) (*claimenvv1.CancelReservationResponse, error) {
+	if err := l.s.grpcEngineFor(ctx).CancelReservation(ctx, req.GetPool(), req.GetReservationId()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &claimenvv1.CancelReservationResponse{}, nil
+}
+
+func (l *lockService) History(ctx context.Context, req *claimenvv1.HistoryRequest) (*claimenvv1.HistoryResponse, error) {
+	var since time.Time
+	if req.GetSince() != nil {
+		since = req.GetSince().AsTime()
+	}
+
+	events, err := l.s.eng.History(ctx, req.GetPool(), req.GetSlotName(), since)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &claimenvv1.HistoryResponse{}
+	for _, ev := range events {
+		resp.Events = append(resp.Events, &claimenvv1.Event{
+			Pool:        ev.Pool,
+			SlotName:    ev.SlotName,
+			Kind:        eventKinds[ev.Kind],
+			Holder:      ev.Holder,
+			At:          timestamppb.New(ev.At),
+			Annotations: ev.Annotations,
+		})
+	}
+	return resp, nil
+}
+
+func (l *lockService) Status(ctx context.Context, req *claimenvv1.StatusRequest) (*claimenvv1.StatusResponse, error) {
+	statuses, err := l.s.eng.Status(ctx, req.GetPool())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &claimenvv1.StatusResponse{}
+	for i := range statuses {
+		resp.Slots = append(resp.Slots, slotStatusToProto(&statuses[i]))
+	}
+	return resp, nil
+}
+
+func (l *lockService) SetSlotState(ctx context.Context, req *claimenvv1.SetSlotStateRequest) (*claimenvv1.SetSlotStateResponse, error) {
+	var state lockstore.SlotState
+	for s, ps := range slotStates {
+		if ps == req.GetState() {
+			state = s
+		}
+	}
+	if state == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid slot state: %v", req.GetState())
+	}
+
+	if _, err := l.s.grpcEngineFor(ctx).SetSlotState(ctx, req.GetPool(), req.GetSlotName(), state, req.GetReason()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &claimenvv1.SetSlotStateResponse{}, nil
+}
+
+func (l *lockService) ValidateLease(ctx context.Context, req *claimenvv1.ValidateLeaseRequest) (*claimenvv1.Claim, error) {
+	claim, err := l.s.eng.LockStore.ValidateLease(ctx, req.GetPool(), req.GetLeaseId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := checkLeaseHolder(ctx, claim.Holder); err != nil {
+		return nil, err
+	}
+	return claimToProto(claim), nil
+}
+
+func (l *lockService) WatchPool(req *claimenvv1.WatchPoolRequest, stream claimenvv1.LockService_WatchPoolServer) error {
+	if err := l.s.checkPool(req); err != nil {
+		return err
+	}
+
+	interval := l.s.WatchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	// last holds the status last sent for each slot
+	last := make(map[string]*claimenvv1.SlotStatus)
+	err := l.s.eng.Watch(stream.Context(), req.GetPool(), interval, func(statuses []lockstore.SlotStatus) error {
+		resp := &claimenvv1.WatchPoolResponse{}
+		for i := range statuses {
+			ps := slotStatusToProto(&statuses[i])
+			if prev, ok := last[ps.SlotName]; ok && proto.Equal(prev, ps) {
+				continue
+			}
+			last[ps.SlotName] = ps
+			resp.Slots = append(resp.Slots, ps)
+		}
+		if len(resp.Slots) == 0 {
+			return nil
+		}
+		return stream.Send(resp)
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+var slotStates = map[lockstore.SlotState]claimenvv1.SlotState{
+	lockstore.SlotActive:      claimenvv1.SlotState_SLOT_STATE_ACTIVE,
+	lockstore.SlotDraining:    claimenvv1.SlotState_SLOT_STATE_DRAINING,
+	lockstore.SlotQuarantined: claimenvv1.SlotState_SLOT_STATE_QUARANTINED,
+}
+
+var eventKinds = map[lockstore.EventKind]claimenvv1.EventKind{
+	lockstore.EventClaim:   claimenvv1.EventKind_EVENT_KIND_CLAIM,
+	lockstore.EventRenew:   claimenvv1.EventKind_EVENT_KIND_RENEW,
+	lockstore.EventRelease: claimenvv1.EventKind_EVENT_KIND_RELEASE,
+	lockstore.EventExpire:  claimenvv1.EventKind_EVENT_KIND_EXPIRE,
+	lockstore.EventRevoke:  claimenvv1.EventKind_EVENT_KIND_REVOKE,
+}
+
+// claimToProto converts a claim without its lease ID. Only Claim and Renew
+// return the caller's own lease ID, via leaseToProto.
+func claimToProto(c *lockstore.Claim) *claimenvv1.Claim {
+	if c == nil {
+		return nil
+	}
+	return &claimenvv1.Claim{
+		Pool:        c.Pool,
+		SlotName:    c.SlotName,
+		Holder:      c.Holder,
+		ClaimedAt:   timestamppb.New(c.ClaimedAt),
+		ExpiresAt:   timestamppb.New(c.ExpiresAt),
+		Annotations: c.Annotations,
+	}
+}
+
+func leaseToProto(lf *lease.LeaseFile) *claimenvv1.Claim {
+	return &claimenvv1.Claim{
+		Pool:        lf.Pool,
+		SlotName:    lf.SlotName,
+		LeaseId:     lf.LeaseID,
+		Holder:      lf.Holder,
+		ClaimedAt:   timestamppb.New(lf.ClaimedAt),
+		ExpiresAt:   timestamppb.New(lf.ExpiresAt),
+		Annotations: lf.Annotations,
+	}
+}
+
+func reservationToProto(r *lockstore.Reservation) *claimenvv1.Reservation {
+	return &claimenvv1.Reservation{
+		Id:       r.ID,
+		Pool:     r.Pool,
+		SlotName: r.SlotName,
+		Holder:   r.Holder,
+		From:     timestamppb.New(r.From),
+		Until:    timestamppb.New(r.Until),
+	}
+}
+
+func slotStatusToProto(s *lockstore.SlotStatus) *claimenvv1.SlotStatus {
+	ps := &claimenvv1.SlotStatus{
+		SlotName: s.SlotName,
+		Claimed:  s.Claimed,
+		Expired:  s.Expired,
+		Claim:    claimToProto(s.Claim),
+		State:    slotStates[s.State],
+		Reason:   s.Reason,
+		Phase:    s.Phase(),
+	}
+	if s.ReleasedAt != nil {
+		ps.ReleasedAt = timestamppb.New(*s.ReleasedAt)
+	}
+	if s.CoolingUntil != nil {
+		ps.CoolingUntil = timestamppb.New(*s.CoolingUntil)
+	}
+	for i := range s.Reservations {
+		ps.Reservations = append(ps.Reservations, reservationToProto(&s.Reservations[i]))
+	}
+	return ps
+}
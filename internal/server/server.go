@@ -0,0 +1,336 @@
+// Package server exposes engine operations over an authenticated HTTP/JSON
+// API, so CI runners can coordinate through a central service instead of
+// holding backend credentials themselves.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lease"
+)
+
+// Server serves the claimenv API for an engine. Hooks, resets, audit events
+// and notifications all run on the server, under its own config.
+type Server struct {
+	eng *engine.Engine
+
+	// tokens maps each accepted bearer token to its name and scope.
+	tokens map[string]Token
+
+	mux *http.ServeMux
+
+	// WatchInterval is how often WatchPool streams poll lock stores that
+	// can't push changes. Defaults to 2 seconds.
+	WatchInterval time.Duration
+}
+
+// Token describes a bearer token accepted by the server.
+type Token struct {
+	// Name identifies callers using the token when they don't send their own
+	// identity.
+	Name string
+
+	// HolderPrefix limits the holders callers using the token may act as to
+	// those starting with it, so one token's callers can't claim, look up or
+	// use another's leases. It defaults to Name.
+	HolderPrefix string
+
+	// Admin allows the administrative gRPC methods: Revoke, Reap, Reserve,
+	// CancelReservation and SetSlotState.
+	Admin bool
+}
+
+// mayActAs reports whether callers using the token may act as holder.
+func (t Token) mayActAs(holder string) bool {
+	prefix := t.HolderPrefix
+	if prefix == "" {
+		prefix = t.Name
+	}
+	return strings.HasPrefix(holder, prefix)
+}
+
+// New creates a server for eng accepting the given bearer tokens, keyed by
+// token.
+func New(eng *engine.Engine, tokens map[string]Token) *Server {
+	s := &Server{eng: eng, tokens: tokens, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("POST /v1/pools/{pool}/claims", s.claim)
+	s.mux.HandleFunc("GET /v1/pools/{pool}/claims", s.findByHolder)
+	s.mux.HandleFunc("GET /v1/pools/{pool}/status", s.status)
+	s.mux.HandleFunc("GET /v1/pools/{pool}/lease", s.getLease)
+	s.mux.HandleFunc("POST /v1/pools/{pool}/lease/renew", s.renew)
+	s.mux.HandleFunc("DELETE /v1/pools/{pool}/lease", s.release)
+	s.mux.HandleFunc("GET /v1/pools/{pool}/lease/env", s.readAll)
+	s.mux.HandleFunc("GET /v1/pools/{pool}/lease/env/{key}", s.readKey)
+	s.mux.HandleFunc("PUT /v1/pools/{pool}/lease/env/{key}", s.writeKey)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := s.authorize(r.Header.Get("Authorization"))
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(w, http.StatusUnauthorized, api.Error{Error: "missing or invalid bearer token"})
+		return
+	}
+	r = r.WithContext(context.WithValue(r.Context(), tokenKey{}, token))
+
+	if pool := poolFromPath(r.URL.Path); pool != "" {
+		if _, ok := s.eng.Cfg.Pools[pool]; !ok {
+			writeJSON(w, http.StatusNotFound, api.Error{Error: fmt.Sprintf("pool %q not found in config", pool)})
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+type tokenKey struct{}
+
+// authorize returns the bearer token in an Authorization header, and whether
+// it's one of the server's tokens.
+func (s *Server) authorize(header string) (Token, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return Token{}, false
+	}
+	for t, tok := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return tok, true
+		}
+	}
+	return Token{}, false
+}
+
+// poolFromPath returns the pool segment of a /v1/pools/{pool}/... path.
+func poolFromPath(path string) string {
+	rest, ok := strings.CutPrefix(path, "/v1/pools/")
+	if !ok {
+		return ""
+	}
+	pool, _, _ := strings.Cut(rest, "/")
+	return pool
+}
+
+// engineAs returns a copy of the engine acting as identity, or else as the
+// name of the caller's token. Audit events then name the CI job rather than
+// the server.
+func (s *Server) engineAs(ctx context.Context, identity string) *engine.Engine {
+	if identity == "" {
+		token, _ := ctx.Value(tokenKey{}).(Token)
+		identity = token.Name
+	}
+
+	e := *s.eng
+	e.Identity = identity
+	return &e
+}
+
+// holderEngine is engineAs for calls that pick a claim by the engine's
+// identity, which the caller's token must be allowed to act as.
+func (s *Server) holderEngine(ctx context.Context, identity string) (*engine.Engine, error) {
+	e := s.engineAs(ctx, identity)
+	if token, _ := ctx.Value(tokenKey{}).(Token); !token.mayActAs(e.Identity) {
+		return nil, fmt.Errorf("token %q may not act as %q", token.Name, e.Identity)
+	}
+	return e, nil
+}
+
+// engineFor is engineAs for HTTP requests, acting as the identity the caller
+// sent.
+func (s *Server) engineFor(r *http.Request) *engine.Engine {
+	return s.engineAs(r.Context(), r.Header.Get(api.IdentityHeader))
+}
+
+// holderEngineFor is holderEngine for HTTP requests, acting as holder or else
+// as the identity the caller sent. It writes a 403 if the caller may not.
+func (s *Server) holderEngineFor(w http.ResponseWriter, r *http.Request, holder string) (*engine.Engine, bool) {
+	if holder == "" {
+		holder = r.Header.Get(api.IdentityHeader)
+	}
+	e, err := s.holderEngine(r.Context(), holder)
+	if err != nil {
+		writeJSON(w, http.StatusForbidden, api.Error{Error: err.Error()})
+		return nil, false
+	}
+	return e, true
+}
+
+// leaseFor rebuilds the lease named by the request's lease header, which must
+// be held by a holder the caller's token may act as.
+func (s *Server) leaseFor(w http.ResponseWriter, r *http.Request) (*lease.LeaseFile, bool) {
+	leaseID := r.Header.Get(api.LeaseHeader)
+	if leaseID == "" {
+		writeJSON(w, http.StatusBadRequest, api.Error{Error: api.LeaseHeader + " header is required"})
+		return nil, false
+	}
+
+	lf, err := s.eng.Lease(r.Context(), r.PathValue("pool"), leaseID)
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+	if token, _ := r.Context().Value(tokenKey{}).(Token); !token.mayActAs(lf.Holder) {
+		writeJSON(w, http.StatusForbidden, api.Error{Error: fmt.Sprintf("token %q may not use the leases of %q", token.Name, lf.Holder)})
+		return nil, false
+	}
+	return lf, true
+}
+
+func (s *Server) claim(w http.ResponseWriter, r *http.Request) {
+	var req api.ClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, api.Error{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+	if req.Holder == "" {
+		writeJSON(w, http.StatusBadRequest, api.Error{Error: "holder is required"})
+		return
+	}
+
+	e, ok := s.holderEngineFor(w, r, req.Holder)
+	if !ok {
+		return
+	}
+
+	lf, err := e.Claim(r.Context(), r.PathValue("pool"), req.Annotations)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, lf)
+}
+
+// findByHolder returns the lease held by the caller. Callers can only look up
+// their own identity, so they get back the same lease a repeat claim would.
+func (s *Server) findByHolder(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.holderEngineFor(w, r, "")
+	if !ok {
+		return
+	}
+	if holder := r.URL.Query().Get("holder"); holder != "" && holder != e.Identity {
+		writeJSON(w, http.StatusForbidden, api.Error{Error: fmt.Sprintf("can't look up the claims of %q as %q", holder, e.Identity)})
+		return
+	}
+
+	lf, err := e.Attach(r.Context(), r.PathValue("pool"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lf)
+}
+
+// status returns the pool's slot statuses without lease IDs.
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	statuses, err := s.eng.Status(r.Context(), r.PathValue("pool"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.PublicStatuses(statuses))
+}
+
+func (s *Server) getLease(w http.ResponseWriter, r *http.Request) {
+	if lf, ok := s.leaseFor(w, r); ok {
+		writeJSON(w, http.StatusOK, lf)
+	}
+}
+
+func (s *Server) renew(w http.ResponseWriter, r *http.Request) {
+	lf, ok := s.leaseFor(w, r)
+	if !ok {
+		return
+	}
+	e := s.engineFor(r)
+
+	renewed, err := e.Renew(r.Context(), lf)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, renewed)
+}
+
+func (s *Server) release(w http.ResponseWriter, r *http.Request) {
+	lf, ok := s.leaseFor(w, r)
+	if !ok {
+		return
+	}
+	e := s.engineFor(r)
+
+	if err := e.Release(r.Context(), lf); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) readAll(w http.ResponseWriter, r *http.Request) {
+	lf, ok := s.leaseFor(w, r)
+	if !ok {
+		return
+	}
+	e := s.engineFor(r)
+
+	values, err := e.ReadAll(r.Context(), lf)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+func (s *Server) readKey(w http.ResponseWriter, r *http.Request) {
+	lf, ok := s.leaseFor(w, r)
+	if !ok {
+		return
+	}
+	e := s.engineFor(r)
+
+	val, err := e.ReadKey(r.Context(), lf, r.PathValue("key"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.Value{Value: val})
+}
+
+func (s *Server) writeKey(w http.ResponseWriter, r *http.Request) {
+	lf, ok := s.leaseFor(w, r)
+	if !ok {
+		return
+	}
+	e := s.engineFor(r)
+
+	var body api.Value
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, api.Error{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	if err := e.WriteKey(r.Context(), lf, r.PathValue("key"), body.Value); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, code := api.StatusFor(err)
+	writeJSON(w, status, api.Error{Error: err.Error(), Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,366 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/internal/server"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	lockhttp "github.com/Kashuab/claimenv/pkg/lockstore/httpstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	claimenvv1 "github.com/Kashuab/claimenv/pkg/proto/claimenv/v1"
+	secrethttp "github.com/Kashuab/claimenv/pkg/secretstore/httpstore"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testTokens are the bearer tokens accepted by test servers.
+var testTokens = map[string]server.Token{
+	"s3cret": {Name: "ci", HolderPrefix: "gitlab-"},
+	"0ther":  {Name: "github", HolderPrefix: "github-"},
+	"adm1n":  {Name: "ops", Admin: true},
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Pools: map[string]config.PoolConfig{
+			"testpool": {
+				Keys:  []string{"SHOPIFY_API_KEY", "APP_URL"},
+				Slots: []config.SlotConfig{{Name: "alpha"}},
+				TTL:   time.Hour,
+			},
+		},
+	}
+}
+
+// remoteEngine returns an engine using the http backends against a server
+// backed by memory stores, and the server's secret store.
+func remoteEngine(t *testing.T, token string) (*engine.Engine, *secretmem.Store) {
+	url, ss := remoteServer(t)
+	return clientEngine(url, token, "gitlab-job-1"), ss
+}
+
+// remoteServer starts a server backed by memory stores and returns its URL
+// and secret store.
+func remoteServer(t *testing.T) (string, *secretmem.Store) {
+	ss := secretmem.New()
+	srvEngine := &engine.Engine{
+		Cfg:         testConfig(),
+		LockStore:   lockmem.New(),
+		SecretStore: ss,
+		Identity:    "server",
+	}
+
+	srv := httptest.NewServer(server.New(srvEngine, testTokens))
+	t.Cleanup(srv.Close)
+	return srv.URL, ss
+}
+
+// clientEngine returns an engine using the http backends against the server at
+// url, sending token and identity.
+func clientEngine(url, token, identity string) *engine.Engine {
+	client := &api.Client{URL: url, Token: token, Identity: identity}
+	return &engine.Engine{
+		Cfg:         testConfig(),
+		LockStore:   lockhttp.New(client),
+		SecretStore: secrethttp.New(client),
+		Identity:    identity,
+	}
+}
+
+func TestRemoteClaimReadWriteRelease(t *testing.T) {
+	e, ss := remoteEngine(t, "s3cret")
+	ctx := context.Background()
+
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+
+	lf, err := e.Claim(ctx, "testpool", map[string]string{"branch": "main"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if lf.SlotName != "alpha" || lf.Holder != "gitlab-job-1" || lf.Annotations["branch"] != "main" {
+		t.Errorf("unexpected lease: %+v", lf)
+	}
+
+	// The only slot is taken, and the sentinel error survives the round trip
+	e.Identity = "gitlab-job-2"
+	if _, err := e.Claim(ctx, "testpool", nil); !errors.Is(err, lockstore.ErrPoolExhausted) {
+		t.Errorf("expected ErrPoolExhausted, got %v", err)
+	}
+	e.Identity = "gitlab-job-1"
+
+	val, err := e.ReadKey(ctx, lf, "SHOPIFY_API_KEY")
+	if err != nil {
+		t.Fatalf("ReadKey failed: %v", err)
+	}
+	if val != "key-alpha" {
+		t.Errorf("expected 'key-alpha', got %q", val)
+	}
+
+	if err := e.WriteKey(ctx, lf, "APP_URL", "https://preview.example.com"); err != nil {
+		t.Fatalf("WriteKey failed: %v", err)
+	}
+	if got, _ := ss.Read(ctx, "alpha-app-url"); got != "https://preview.example.com" {
+		t.Errorf("expected write to reach the server's store, got %q", got)
+	}
+
+	if _, err := e.Renew(ctx, lf); err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !statuses[0].Claimed || statuses[0].Claim.Holder != "gitlab-job-1" {
+		t.Errorf("expected alpha to be claimed by gitlab-job-1, got %+v", statuses[0])
+	}
+
+	if err := e.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := e.ReadKey(ctx, lf, "SHOPIFY_API_KEY"); !errors.Is(err, lockstore.ErrLeaseNotFound) {
+		t.Errorf("expected ErrLeaseNotFound after release, got %v", err)
+	}
+}
+
+func TestRemoteLeaseIDsStayWithTheirHolder(t *testing.T) {
+	e, _ := remoteEngine(t, "s3cret")
+	ctx := context.Background()
+
+	lf, err := e.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	statuses, err := e.Status(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if c := statuses[0].Claim; c == nil || c.Holder != "gitlab-job-1" || c.LeaseID != "" {
+		t.Errorf("expected alpha's claim without its lease ID, got %+v", c)
+	}
+
+	// The holder can recover its own lease, but not look up anyone else's
+	attached, err := e.Attach(ctx, "testpool")
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if attached.LeaseID != lf.LeaseID {
+		t.Errorf("expected lease %s, got %s", lf.LeaseID, attached.LeaseID)
+	}
+	if _, err := e.LockStore.FindByHolder(ctx, "testpool", "gitlab-job-2"); err == nil {
+		t.Error("expected looking up another holder's claim to fail")
+	}
+}
+
+func TestTokenCannotUseAnotherTokensLeases(t *testing.T) {
+	url, ss := remoteServer(t)
+	ctx := context.Background()
+
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+
+	victim := clientEngine(url, "s3cret", "gitlab-job-1")
+	lf, err := victim.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// Another token's caller claiming, looking up or reading as the victim is
+	// refused, whatever identity it sends
+	attacker := clientEngine(url, "0ther", "gitlab-job-1")
+	if got, err := attacker.Claim(ctx, "testpool", nil); err == nil {
+		t.Errorf("expected a claim as another token's holder to fail, got lease %+v", got)
+	}
+	if _, err := attacker.Attach(ctx, "testpool"); err == nil {
+		t.Error("expected looking up another token's holder to fail")
+	}
+	if val, err := attacker.ReadKey(ctx, lf, "SHOPIFY_API_KEY"); err == nil {
+		t.Errorf("expected reading another token's lease to fail, got %q", val)
+	}
+}
+
+func TestRejectsInvalidToken(t *testing.T) {
+	e, _ := remoteEngine(t, "wrong")
+
+	_, err := e.Claim(context.Background(), "testpool", nil)
+	if err == nil {
+		t.Fatal("expected claim with an invalid token to fail")
+	}
+
+	srv := httptest.NewServer(server.New(&engine.Engine{Cfg: testConfig()}, testTokens))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/pools/testpool/status")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", resp.StatusCode)
+	}
+}
+
+// grpcClient returns a gRPC client sending token to a server backed by memory
+// stores.
+func grpcClient(t *testing.T, token string) claimenvv1.LockServiceClient {
+	return grpcClients(t, token)[0]
+}
+
+// grpcClients returns a gRPC client for each token, all talking to the same
+// server backed by memory stores.
+func grpcClients(t *testing.T, tokens ...string) []claimenvv1.LockServiceClient {
+	srvEngine := &engine.Engine{
+		Cfg:         testConfig(),
+		LockStore:   lockmem.New(),
+		SecretStore: secretmem.New(),
+		Identity:    "server",
+	}
+	srv := server.New(srvEngine, testTokens)
+	srv.WatchInterval = 10 * time.Millisecond
+
+	lis := bufconn.Listen(1 << 20)
+	g := srv.GRPC()
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	var clients []claimenvv1.LockServiceClient
+	for _, token := range tokens {
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithPerRPCCredentials(bearerToken(token)),
+		)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		clients = append(clients, claimenvv1.NewLockServiceClient(conn))
+	}
+	return clients
+}
+
+type bearerToken string
+
+func (b bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+func (bearerToken) RequireTransportSecurity() bool { return false }
+
+func TestGRPCClaimAndWatchPool(t *testing.T) {
+	client := grpcClient(t, "s3cret")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchPool(ctx, &claimenvv1.WatchPoolRequest{Pool: "testpool"})
+	if err != nil {
+		t.Fatalf("WatchPool failed: %v", err)
+	}
+	first, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if len(first.Slots) != 1 || first.Slots[0].Phase != "free" {
+		t.Fatalf("expected the initial snapshot to show alpha free, got %v", first.Slots)
+	}
+
+	claim, err := client.Claim(ctx, &claimenvv1.ClaimRequest{Pool: "testpool", Holder: "gitlab-deploy-1"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if claim.SlotName != "alpha" || claim.Holder != "gitlab-deploy-1" {
+		t.Errorf("unexpected claim: %v", claim)
+	}
+
+	changed, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if len(changed.Slots) != 1 || changed.Slots[0].Phase != "claimed" || changed.Slots[0].Claim.GetHolder() != "gitlab-deploy-1" {
+		t.Errorf("expected alpha to be pushed as claimed by gitlab-deploy-1, got %v", changed.Slots)
+	}
+	if id := changed.Slots[0].Claim.GetLeaseId(); id != "" {
+		t.Errorf("expected the pushed claim without its lease ID, got %q", id)
+	}
+
+	// The exhausted pool is reported with its error code
+	_, err = client.Claim(ctx, &claimenvv1.ClaimRequest{Pool: "testpool", Holder: "gitlab-deploy-2"})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+	if d := st.Details(); len(d) != 1 || d[0].(*errdetails.ErrorInfo).GetReason() != "pool_exhausted" {
+		t.Errorf("expected a pool_exhausted ErrorInfo, got %v", d)
+	}
+
+	if _, err := client.Release(ctx, &claimenvv1.ReleaseRequest{Pool: "testpool", LeaseId: claim.LeaseId}); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	freed, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if len(freed.Slots) != 1 || freed.Slots[0].Phase != "free" {
+		t.Errorf("expected alpha to be pushed as free, got %v", freed.Slots)
+	}
+}
+
+func TestGRPCRejectsInvalidToken(t *testing.T) {
+	client := grpcClient(t, "wrong")
+
+	_, err := client.Status(context.Background(), &claimenvv1.StatusRequest{Pool: "testpool"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestGRPCAdminMethodsNeedAdminToken(t *testing.T) {
+	ctx := context.Background()
+	req := &claimenvv1.SetSlotStateRequest{Pool: "testpool", SlotName: "alpha", State: claimenvv1.SlotState_SLOT_STATE_DRAINING}
+
+	if _, err := grpcClient(t, "s3cret").SetSlotState(ctx, req); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for a CI token, got %v", err)
+	}
+	if _, err := grpcClient(t, "adm1n").SetSlotState(ctx, req); err != nil {
+		t.Errorf("expected SetSlotState to succeed with an admin token, got %v", err)
+	}
+}
+
+func TestGRPCTokenCannotUseAnotherTokensLeases(t *testing.T) {
+	clients := grpcClients(t, "s3cret", "0ther")
+	victim, attacker := clients[0], clients[1]
+	ctx := context.Background()
+
+	claim, err := victim.Claim(ctx, &claimenvv1.ClaimRequest{Pool: "testpool", Holder: "gitlab-deploy-1"})
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if got, err := attacker.Claim(ctx, &claimenvv1.ClaimRequest{Pool: "testpool", Holder: "gitlab-deploy-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied claiming as another token's holder, got %v, %v", got, err)
+	}
+	if _, err := attacker.ReleaseByHolder(ctx, &claimenvv1.ReleaseByHolderRequest{Pool: "testpool", Holder: "gitlab-deploy-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied releasing another token's holder, got %v", err)
+	}
+	if _, err := attacker.Renew(ctx, &claimenvv1.RenewRequest{Pool: "testpool", LeaseId: claim.LeaseId}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied renewing another token's lease, got %v", err)
+	}
+
+	if _, err := victim.ValidateLease(ctx, &claimenvv1.ValidateLeaseRequest{Pool: "testpool", LeaseId: claim.LeaseId}); err != nil {
+		t.Errorf("expected the victim's lease to be intact, got %v", err)
+	}
+}
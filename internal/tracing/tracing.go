@@ -0,0 +1,110 @@
+// Package tracing sets up OpenTelemetry tracing for claimenv and holds the
+// span attributes shared by the engine and the backend stores. Spans carry
+// pool, slot and holder names but never secret values or lease IDs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys.
+const (
+	PoolKey   = attribute.Key("claimenv.pool")
+	SlotKey   = attribute.Key("claimenv.slot")
+	HolderKey = attribute.Key("claimenv.holder")
+	EnvKey    = attribute.Key("claimenv.key")
+	SecretKey = attribute.Key("claimenv.secret")
+)
+
+// propagator reads and writes W3C trace context, the format of TRACEPARENT.
+var propagator = propagation.TraceContext{}
+
+// Enabled reports whether an OTLP endpoint is configured through the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT variables.
+func Enabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider exporting spans over OTLP/HTTP.
+// The exporter is configured through the standard OTEL_* environment
+// variables; if no endpoint is set, tracing stays disabled and spans are
+// no-ops. The returned function flushes pending spans and must be called
+// before exiting.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES win
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "claimenv")),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// ContextFromEnv returns ctx carrying the remote span context from the
+// TRACEPARENT and TRACESTATE environment variables, so spans join the trace
+// of the CI job that ran claimenv.
+func ContextFromEnv(ctx context.Context) context.Context {
+	return propagator.Extract(ctx, propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	})
+}
+
+// Environ returns TRACEPARENT and TRACESTATE variables for the span in ctx,
+// for passing the trace on to child processes. It returns nil if ctx has no
+// valid span.
+func Environ(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	var env []string
+	if tp := carrier.Get("traceparent"); tp != "" {
+		env = append(env, "TRACEPARENT="+tp)
+	}
+	if ts := carrier.Get("tracestate"); ts != "" {
+		env = append(env, "TRACESTATE="+ts)
+	}
+	return env
+}
+
+// End records *err on span, if any, and ends it. It's meant to be deferred
+// with a pointer to the caller's named error result.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
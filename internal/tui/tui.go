@@ -0,0 +1,627 @@
+// Package tui is the full-screen pool administration interface behind
+// "claimenv tui". It shows every pool in the config with live slot status and
+// runs renew, release, revoke, quarantine and history on the selected slot.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historyWindow is how far back the history view looks.
+const historyWindow = 7 * 24 * time.Hour
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	tabStyle      = lipgloss.NewStyle().Padding(0, 1)
+	activeTab     = tabStyle.Reverse(true)
+	headerStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+
+	phaseStyles = map[string]lipgloss.Style{
+		"free":        lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		"claimed":     lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		"expired":     lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		"cooling":     lipgloss.NewStyle().Foreground(lipgloss.Color("6")),
+		"draining":    lipgloss.NewStyle().Foreground(lipgloss.Color("5")),
+		"quarantined": lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true),
+	}
+)
+
+// Run shows the interface until the user quits. Slot status is pushed by lock
+// stores implementing lockstore.Watcher and polled every interval otherwise.
+func Run(ctx context.Context, eng *engine.Engine, interval time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Hook output and warnings would garble the screen, so they're shown in
+	// the status line instead
+	log := &lastLine{}
+	e := *eng
+	e.Log = log
+
+	m := New(ctx, &e)
+	m.log = log
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx))
+
+	for _, pool := range m.pools {
+		go func() {
+			err := e.Watch(ctx, pool, interval, func(statuses []lockstore.SlotStatus) error {
+				p.Send(statusMsg{pool: pool, statuses: statuses})
+				return nil
+			})
+			if err != nil {
+				p.Send(statusMsg{pool: pool, err: err})
+			}
+		}()
+	}
+
+	_, err := p.Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// mode is what the keyboard currently drives.
+type mode int
+
+const (
+	browsing   mode = iota
+	confirming      // waiting for y/n on a destructive action
+	entering        // typing a quarantine reason
+	viewing         // looking at a slot's history
+)
+
+// Model is the bubbletea model of the interface.
+type Model struct {
+	ctx context.Context
+	eng *engine.Engine
+	log *lastLine
+
+	pools    []string
+	statuses map[string][]lockstore.SlotStatus
+	errs     map[string]error
+
+	pool int // index into pools
+	slot int // index into the selected pool's statuses
+
+	mode    mode
+	pending func() tea.Cmd // the action awaiting confirmation
+	prompt  string
+	input   string
+	history []lockstore.Event
+
+	message string
+	failed  bool
+
+	now    time.Time
+	width  int
+	height int
+}
+
+// New returns the model for every pool in the engine's config.
+func New(ctx context.Context, eng *engine.Engine) *Model {
+	pools := make([]string, 0, len(eng.Cfg.Pools))
+	for name := range eng.Cfg.Pools {
+		pools = append(pools, name)
+	}
+	sort.Strings(pools)
+
+	return &Model{
+		ctx:      ctx,
+		eng:      eng,
+		pools:    pools,
+		statuses: make(map[string][]lockstore.SlotStatus),
+		errs:     make(map[string]error),
+		now:      time.Now(),
+	}
+}
+
+// statusMsg delivers a pool's latest status, or the error that ended its watch.
+type statusMsg struct {
+	pool     string
+	statuses []lockstore.SlotStatus
+	err      error
+}
+
+// resultMsg reports the outcome of an action.
+type resultMsg struct {
+	message string
+	err     error
+}
+
+// historyMsg delivers the history of the selected slot.
+type historyMsg struct {
+	events []lockstore.Event
+	err    error
+}
+
+// tickMsg redraws the expiry countdowns.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m *Model) Init() tea.Cmd {
+	return tick()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tickMsg:
+		m.now = time.Time(msg)
+		return m, tick()
+	case statusMsg:
+		if msg.err != nil {
+			m.errs[msg.pool] = msg.err
+		} else {
+			delete(m.errs, msg.pool)
+			m.statuses[msg.pool] = msg.statuses
+		}
+		m.clampSlot()
+	case resultMsg:
+		m.setResult(msg.message, msg.err)
+	case historyMsg:
+		if msg.err != nil {
+			m.mode = browsing
+			m.setResult("", msg.err)
+			break
+		}
+		m.history = msg.events
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) setResult(message string, err error) {
+	m.failed = err != nil
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.message = message
+	if line := m.log.String(); line != "" {
+		m.message += " | " + line
+	}
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return tea.Quit
+	}
+
+	switch m.mode {
+	case confirming:
+		m.mode = browsing
+		if key == "y" || key == "Y" {
+			m.message, m.failed = "Working...", false
+			return m.pending()
+		}
+		m.message = "Cancelled"
+		return nil
+
+	case entering:
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.mode = browsing
+			return m.pending()
+		case tea.KeyEsc:
+			m.mode = browsing
+			m.message = "Cancelled"
+		case tea.KeyBackspace:
+			if r := []rune(m.input); len(r) > 0 {
+				m.input = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.input += string(msg.Runes)
+		}
+		return nil
+
+	case viewing:
+		if key == "esc" || key == "q" || key == "h" {
+			m.mode = browsing
+			m.history = nil
+		}
+		return nil
+	}
+
+	if key == "q" {
+		return tea.Quit
+	}
+	if len(m.pools) == 0 {
+		return nil
+	}
+
+	switch key {
+	case "up", "k":
+		if m.slot > 0 {
+			m.slot--
+		}
+	case "down", "j":
+		m.slot++
+		m.clampSlot()
+	case "right", "l", "tab":
+		m.pool = (m.pool + 1) % len(m.pools)
+		m.clampSlot()
+	case "left", "shift+tab":
+		m.pool = (m.pool + len(m.pools) - 1) % len(m.pools)
+		m.clampSlot()
+	case "r":
+		return m.renew()
+	case "d":
+		m.release()
+	case "x":
+		m.revoke()
+	case "p":
+		return m.toggleQuarantine()
+	case "h":
+		return m.showHistory()
+	}
+	return nil
+}
+
+// selected returns the pool name and the status of the selected slot, if any.
+func (m *Model) selected() (string, *lockstore.SlotStatus) {
+	if len(m.pools) == 0 {
+		return "", nil
+	}
+	pool := m.pools[m.pool]
+	statuses := m.statuses[pool]
+	if m.slot >= len(statuses) {
+		return pool, nil
+	}
+	return pool, &statuses[m.slot]
+}
+
+func (m *Model) clampSlot() {
+	if len(m.pools) == 0 {
+		return
+	}
+	if n := len(m.statuses[m.pools[m.pool]]); m.slot >= n {
+		m.slot = max(n-1, 0)
+	}
+}
+
+// activeClaim returns the selected slot's claim, or sets an error message if
+// it has none.
+func (m *Model) activeClaim() (string, *lockstore.SlotStatus, bool) {
+	pool, s := m.selected()
+	if s == nil || s.Claim == nil || !(s.Claimed || s.Expired) {
+		m.message, m.failed = "The selected slot has no claim", true
+		return "", nil, false
+	}
+	return pool, s, true
+}
+
+// confirm asks for y/n before running action.
+func (m *Model) confirm(prompt string, action func() tea.Cmd) {
+	m.mode = confirming
+	m.prompt = prompt + " (y/n)"
+	m.pending = action
+}
+
+func (m *Model) renew() tea.Cmd {
+	pool, s, ok := m.activeClaim()
+	if !ok {
+		return nil
+	}
+	leaseID, slot := s.Claim.LeaseID, s.SlotName
+
+	return func() tea.Msg {
+		lf, err := m.eng.Lease(m.ctx, pool, leaseID)
+		if err != nil {
+			return resultMsg{err: err}
+		}
+		renewed, err := m.eng.Renew(m.ctx, lf)
+		if err != nil {
+			return resultMsg{err: err}
+		}
+		return resultMsg{message: fmt.Sprintf("Renewed %s until %s", slot, renewed.ExpiresAt.Format("15:04:05"))}
+	}
+}
+
+func (m *Model) release() {
+	pool, s, ok := m.activeClaim()
+	if !ok {
+		return
+	}
+	leaseID, slot, holder := s.Claim.LeaseID, s.SlotName, s.Claim.Holder
+
+	m.confirm(fmt.Sprintf("Release %s, held by %s?", slot, holder), func() tea.Cmd {
+		return func() tea.Msg {
+			lf, err := m.eng.Lease(m.ctx, pool, leaseID)
+			if err != nil {
+				return resultMsg{err: err}
+			}
+			if err := m.eng.Release(m.ctx, lf); err != nil {
+				return resultMsg{err: err}
+			}
+			return resultMsg{message: fmt.Sprintf("Released %s", slot)}
+		}
+	})
+}
+
+func (m *Model) revoke() {
+	pool, s, ok := m.activeClaim()
+	if !ok {
+		return
+	}
+	slot, holder := s.SlotName, s.Claim.Holder
+
+	m.confirm(fmt.Sprintf("Revoke %s's claim on %s?", holder, slot), func() tea.Cmd {
+		return func() tea.Msg {
+			if _, err := m.eng.Revoke(m.ctx, pool, slot); err != nil {
+				return resultMsg{err: err}
+			}
+			return resultMsg{message: fmt.Sprintf("Revoked %s's claim on %s", holder, slot)}
+		}
+	})
+}
+
+// toggleQuarantine asks for a reason and quarantines the selected slot, or
+// restores it if it's already quarantined.
+func (m *Model) toggleQuarantine() tea.Cmd {
+	pool, s := m.selected()
+	if s == nil {
+		return nil
+	}
+	slot := s.SlotName
+
+	if s.State == lockstore.SlotQuarantined {
+		m.confirm(fmt.Sprintf("Restore %s to rotation?", slot), func() tea.Cmd {
+			return m.setState(pool, slot, lockstore.SlotActive, "")
+		})
+		return nil
+	}
+
+	m.mode = entering
+	m.prompt = fmt.Sprintf("Reason for quarantining %s (enter to confirm, esc to cancel): ", slot)
+	m.input = ""
+	m.pending = func() tea.Cmd {
+		if strings.TrimSpace(m.input) == "" {
+			m.message, m.failed = "A reason is required to quarantine a slot", true
+			return nil
+		}
+		return m.setState(pool, slot, lockstore.SlotQuarantined, m.input)
+	}
+	return nil
+}
+
+func (m *Model) setState(pool, slot string, state lockstore.SlotState, reason string) tea.Cmd {
+	return func() tea.Msg {
+		revoked, err := m.eng.SetSlotState(m.ctx, pool, slot, state, reason)
+		if err != nil {
+			return resultMsg{err: err}
+		}
+		if revoked != nil {
+			return resultMsg{message: fmt.Sprintf("%s is now %s; revoked claim of %s", slot, state, revoked.Holder)}
+		}
+		return resultMsg{message: fmt.Sprintf("%s is now %s", slot, state)}
+	}
+}
+
+func (m *Model) showHistory() tea.Cmd {
+	pool, s := m.selected()
+	if s == nil {
+		return nil
+	}
+	slot := s.SlotName
+
+	m.mode = viewing
+	m.history = nil
+	return func() tea.Msg {
+		events, err := m.eng.History(m.ctx, pool, slot, time.Now().Add(-historyWindow))
+		return historyMsg{events: events, err: err}
+	}
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s  acting as %s\n\n", titleStyle.Render("claimenv"), m.eng.Identity)
+	if len(m.pools) == 0 {
+		b.WriteString("No pools configured.\n")
+		return b.String()
+	}
+
+	var tabs []string
+	for i, pool := range m.pools {
+		label := pool
+		if statuses, ok := m.statuses[pool]; ok {
+			claimed := 0
+			for _, s := range statuses {
+				if s.Claimed {
+					claimed++
+				}
+			}
+			label = fmt.Sprintf("%s %d/%d", pool, claimed, len(statuses))
+		}
+		if i == m.pool {
+			tabs = append(tabs, activeTab.Render(label))
+		} else {
+			tabs = append(tabs, tabStyle.Render(label))
+		}
+	}
+	b.WriteString(strings.Join(tabs, " ") + "\n\n")
+
+	if m.mode == viewing {
+		m.viewHistory(&b)
+	} else {
+		m.viewSlots(&b)
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.mode == confirming || m.mode == entering:
+		b.WriteString(m.prompt + m.input + "\n")
+	case m.failed:
+		b.WriteString(errorStyle.Render(m.message) + "\n")
+	default:
+		b.WriteString(m.message + "\n")
+	}
+
+	if m.mode == viewing {
+		b.WriteString(helpStyle.Render("esc back · q back · ctrl+c quit"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓ slot · ←/→ pool · r renew · d release · x revoke · p quarantine/restore · h history · q quit"))
+	}
+	return b.String()
+}
+
+func (m *Model) viewSlots(b *strings.Builder) {
+	pool := m.pools[m.pool]
+	if err := m.errs[pool]; err != nil {
+		b.WriteString(errorStyle.Render(err.Error()) + "\n")
+		return
+	}
+	statuses, ok := m.statuses[pool]
+	if !ok {
+		b.WriteString("Loading...\n")
+		return
+	}
+
+	rows := [][]string{{"SLOT", "STATUS", "HOLDER", "EXPIRES IN", "ANNOTATIONS"}}
+	for _, s := range statuses {
+		holder, expires, annotations := "-", "-", "-"
+		if (s.Claimed || s.Expired) && s.Claim != nil {
+			holder = s.Claim.Holder
+			expires = countdown(s.Claim.ExpiresAt.Sub(m.now))
+			annotations = formatAnnotations(s.Claim.Annotations)
+		} else if s.CoolingUntil != nil {
+			expires = countdown(s.CoolingUntil.Sub(m.now))
+		}
+		status := s.Phase()
+		if s.State == lockstore.SlotQuarantined && s.Reason != "" {
+			status += " (" + s.Reason + ")"
+		}
+		rows = append(rows, []string{s.SlotName, status, holder, expires, annotations})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+		}
+		line := strings.Join(cells, "  ")
+
+		switch {
+		case r == 0:
+			line = headerStyle.Render(line)
+		case r-1 == m.slot:
+			line = selectedStyle.Render(line)
+		default:
+			// Color just the status column
+			phase := statuses[r-1].Phase()
+			cells[1] = phaseStyles[phase].Render(cells[1])
+			line = strings.Join(cells, "  ")
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+func (m *Model) viewHistory(b *strings.Builder) {
+	_, s := m.selected()
+	if s == nil {
+		return
+	}
+	fmt.Fprintf(b, "History of %s, last %d days\n\n", s.SlotName, int(historyWindow.Hours()/24))
+	if m.history == nil {
+		b.WriteString("Loading...\n")
+		return
+	}
+	if len(m.history) == 0 {
+		b.WriteString("No events.\n")
+		return
+	}
+
+	// Show the most recent events that fit on screen
+	events := m.history
+	if limit := m.height - 12; limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	for _, ev := range events {
+		fmt.Fprintf(b, "%s  %-8s %s  %s\n", ev.At.Format("2006-01-02 15:04:05"), ev.Kind, ev.Holder, formatAnnotations(ev.Annotations))
+	}
+}
+
+// countdown renders the time left until a deadline.
+func countdown(d time.Duration) string {
+	if d <= 0 {
+		return fmt.Sprintf("%s ago", (-d).Truncate(time.Second))
+	}
+	return d.Truncate(time.Second).String()
+}
+
+// formatAnnotations renders annotations as sorted key=value pairs.
+func formatAnnotations(annotations map[string]string) string {
+	if len(annotations) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + annotations[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// lastLine is an io.Writer that keeps the last line written to it, and
+// forgets it once read.
+type lastLine struct {
+	mu   sync.Mutex
+	line string
+}
+
+func (l *lastLine) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lines := bytes.Split(bytes.TrimSpace(p), []byte("\n"))
+	if last := strings.TrimSpace(string(lines[len(lines)-1])); last != "" {
+		l.line = last
+	}
+	return len(p), nil
+}
+
+func (l *lastLine) String() string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line := l.line
+	l.line = ""
+	return line
+}
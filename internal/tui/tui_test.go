@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testModel(t *testing.T) (*Model, *engine.Engine) {
+	t.Helper()
+
+	e := &engine.Engine{
+		Cfg: &config.Config{
+			Pools: map[string]config.PoolConfig{
+				"checkout": {
+					Keys:  []string{"STRIPE_KEY"},
+					Slots: []config.SlotConfig{{Name: "one"}},
+					TTL:   time.Hour,
+				},
+				"onboard": {
+					Keys:  []string{"SHOPIFY_API_KEY"},
+					Slots: []config.SlotConfig{{Name: "alpha"}, {Name: "beta"}},
+					TTL:   time.Hour,
+				},
+			},
+		},
+		LockStore:   lockmem.New(),
+		SecretStore: secretmem.New(),
+		Identity:    "oncall",
+	}
+	return New(context.Background(), e), e
+}
+
+// refresh feeds the model the current status of every pool, as the watchers
+// in Run would.
+func refresh(t *testing.T, m *Model, e *engine.Engine) {
+	t.Helper()
+	for _, pool := range m.pools {
+		statuses, err := e.Status(context.Background(), pool)
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		m.Update(statusMsg{pool: pool, statuses: statuses})
+	}
+}
+
+func press(m *Model, keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		}
+		_, cmd = m.Update(msg)
+	}
+	return cmd
+}
+
+// run executes an action's command and feeds its result back to the model.
+func run(t *testing.T, m *Model, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("expected a command, message is %q", m.message)
+	}
+	m.Update(cmd())
+}
+
+func TestViewListsAllPools(t *testing.T) {
+	m, e := testModel(t)
+	if _, err := e.Claim(context.Background(), "onboard", map[string]string{"branch": "main"}); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	refresh(t, m, e)
+
+	// Pools are sorted, so onboard is the second tab
+	press(m, "tab")
+	view := m.View()
+	for _, want := range []string{"checkout 0/1", "onboard 1/2", "alpha", "oncall", "branch=main", "1h0m"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestReleaseAsksForConfirmation(t *testing.T) {
+	m, e := testModel(t)
+	ctx := context.Background()
+	e.Identity = "gitlab-job-1"
+	if _, err := e.Claim(ctx, "checkout", nil); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	refresh(t, m, e)
+
+	if cmd := press(m, "d", "n"); cmd != nil {
+		t.Fatal("expected no action after declining")
+	}
+	run(t, m, press(m, "d", "y"))
+	if m.failed {
+		t.Fatalf("release failed: %s", m.message)
+	}
+
+	statuses, _ := e.Status(ctx, "checkout")
+	if statuses[0].Claimed {
+		t.Error("expected the slot to be released")
+	}
+}
+
+func TestQuarantineRequiresReason(t *testing.T) {
+	m, e := testModel(t)
+	ctx := context.Background()
+	refresh(t, m, e)
+
+	press(m, "tab", "down", "p")
+	if cmd := press(m, "enter"); cmd != nil || !m.failed {
+		t.Fatal("expected quarantine without a reason to be refused")
+	}
+
+	press(m, "p", "a", "p", "p", " ", "b", "a", "n", "n", "e", "d")
+	run(t, m, press(m, "enter"))
+
+	statuses, _ := e.Status(ctx, "onboard")
+	if statuses[1].State != lockstore.SlotQuarantined || statuses[1].Reason != "app banned" {
+		t.Errorf("expected beta to be quarantined as 'app banned', got %s (%q)", statuses[1].State, statuses[1].Reason)
+	}
+
+	// Pressing p on a quarantined slot offers to restore it
+	refresh(t, m, e)
+	run(t, m, press(m, "p", "y"))
+	statuses, _ = e.Status(ctx, "onboard")
+	if statuses[1].State != lockstore.SlotActive {
+		t.Errorf("expected beta to be restored, got %s", statuses[1].State)
+	}
+}
@@ -0,0 +1,184 @@
+// Package claimenv is the Go client for claimenv. It claims slots from the
+// pools in a claimenv.yaml, reads their values and releases them, the same
+// way the CLI does, so Go programs and test harnesses don't need to shell out.
+//
+//	c, err := claimenv.New(claimenv.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer c.Close()
+//
+//	err = c.WithLease(ctx, "onboard", func(env map[string]string) error {
+//		return runTests(env["SHOPIFY_API_KEY"])
+//	})
+//
+// Errors from the lock store can be matched with errors.Is against the
+// sentinel errors of the lockstore package, e.g. lockstore.ErrPoolExhausted.
+package claimenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/backend"
+	"github.com/Kashuab/claimenv/internal/config"
+	"github.com/Kashuab/claimenv/internal/engine"
+	"github.com/Kashuab/claimenv/internal/identity"
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+)
+
+// ErrLeaseHeld is returned by WithLease when the client's identity already
+// holds a slot in the pool.
+var ErrLeaseHeld = errors.New("claimenv: identity already holds a slot in the pool")
+
+// Options configures a Client. The zero value reads the config the CLI would
+// use and acts as the CLI's holder identity.
+type Options struct {
+	// ConfigFile is the path of the config. If empty, CLAIMENV_CONFIG is used,
+	// or else claimenv.yaml in the working directory or ~/.config/claimenv.
+	ConfigFile string
+
+	// Identity is the holder recorded on claims. Defaults to the identity the
+	// CLI resolves from the CI environment.
+	Identity string
+
+	// LockStore and SecretStore replace the backends configured in the
+	// config file, e.g. with in-memory stores for tests.
+	LockStore   lockstore.LockStore
+	SecretStore secretstore.SecretStore
+}
+
+// Client claims and releases slots. It is safe for concurrent use if its
+// stores are.
+type Client struct {
+	eng *engine.Engine
+}
+
+// New creates a client from opts.
+func New(opts Options) (*Client, error) {
+	cfg, err := config.ReadFile(opts.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	id := opts.Identity
+	if id == "" {
+		id = identity.Resolve()
+	}
+
+	eng, err := backend.NewEngine(cfg, id, opts.LockStore, opts.SecretStore)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{eng: eng}, nil
+}
+
+// Identity returns the holder identity the client claims as.
+func (c *Client) Identity() string {
+	return c.eng.Identity
+}
+
+// Claim acquires a free slot in the named pool. The annotations (e.g. a
+// branch or pipeline URL) are stored on the claim and shown in status.
+func (c *Client) Claim(ctx context.Context, pool string, annotations map[string]string) (*lease.LeaseFile, error) {
+	return c.eng.Claim(ctx, pool, annotations)
+}
+
+// Renew extends the lease by the pool's TTL and returns the updated lease.
+func (c *Client) Renew(ctx context.Context, lf *lease.LeaseFile) (*lease.LeaseFile, error) {
+	return c.eng.Renew(ctx, lf)
+}
+
+// Release releases the lease, running the pool's release hooks.
+func (c *Client) Release(ctx context.Context, lf *lease.LeaseFile) error {
+	return c.eng.Release(ctx, lf)
+}
+
+// ReadAll returns every value of the leased slot, keyed by env var name.
+func (c *Client) ReadAll(ctx context.Context, lf *lease.LeaseFile) (map[string]string, error) {
+	return c.eng.ReadAll(ctx, lf)
+}
+
+// WithLease claims a slot in the named pool, calls fn with the slot's values
+// and releases the slot when fn returns, even if it fails. The lease is
+// renewed in the background while fn runs, so fn may outlast the pool's TTL.
+//
+// A holder gets one slot per pool, so WithLease returns ErrLeaseHeld rather
+// than share and later release a slot its identity already holds. Concurrent
+// calls need clients with different identities. The http backend can't tell a
+// held slot from a new claim, so there concurrent calls on one identity share
+// the slot until the first of them releases it.
+func (c *Client) WithLease(ctx context.Context, pool string, fn func(env map[string]string) error) (err error) {
+	lf, err := c.Claim(ctx, pool, nil)
+	if err != nil {
+		return err
+	}
+	if lf.Existing {
+		return fmt.Errorf("%w: slot %q in pool %q", ErrLeaseHeld, lf.SlotName, lf.Pool)
+	}
+
+	// Release with a fresh context so a cancelled ctx doesn't leak the slot
+	defer func() {
+		relCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		if relErr := c.Release(relCtx, lf); relErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release slot %q: %w", lf.SlotName, relErr))
+		}
+	}()
+
+	env, err := c.ReadAll(ctx, lf)
+	if err != nil {
+		return err
+	}
+
+	stop := c.keepAlive(ctx, lf)
+	defer func() {
+		if renewErr := stop(); renewErr != nil {
+			err = errors.Join(err, renewErr)
+		}
+	}()
+
+	return fn(env)
+}
+
+// keepAlive renews lf at half its pool's TTL until the returned function is
+// called, which reports the first renewal failure.
+func (c *Client) keepAlive(ctx context.Context, lf *lease.LeaseFile) func() error {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+
+	interval := c.eng.Cfg.Pools[lf.Pool].TTL / 2
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				done <- nil
+				return
+			case <-ticker.C:
+				if _, err := c.Renew(ctx, lf); err != nil && ctx.Err() == nil {
+					done <- fmt.Errorf("failed to renew slot %q: %w", lf.SlotName, err)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+// Close releases resources held by the client's stores. It doesn't release
+// any leases.
+func (c *Client) Close() error {
+	return c.eng.Close()
+}
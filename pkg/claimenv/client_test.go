@@ -0,0 +1,126 @@
+package claimenv_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kashuab/claimenv/pkg/claimenv"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	lockmem "github.com/Kashuab/claimenv/pkg/lockstore/memory"
+	secretmem "github.com/Kashuab/claimenv/pkg/secretstore/memory"
+)
+
+const testConfig = `backend:
+  lock:
+    type: memory
+  secrets:
+    type: memory
+pools:
+  testpool:
+    ttl: 1h
+    keys:
+      - SHOPIFY_API_KEY
+    slots:
+      - name: alpha
+`
+
+func testClient(t *testing.T) (*claimenv.Client, *lockmem.Store) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "claimenv.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := lockmem.New()
+	ss := secretmem.New()
+	ss.Seed("alpha-shopify-api-key", "key-alpha")
+
+	c, err := claimenv.New(claimenv.Options{
+		ConfigFile:  path,
+		Identity:    "test-holder",
+		LockStore:   ls,
+		SecretStore: ss,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c, ls
+}
+
+func TestWithLease(t *testing.T) {
+	c, ls := testClient(t)
+	ctx := context.Background()
+
+	var got string
+	err := c.WithLease(ctx, "testpool", func(env map[string]string) error {
+		got = env["SHOPIFY_API_KEY"]
+
+		// The only slot is held while fn runs
+		if _, err := ls.FindByHolder(ctx, "testpool", "test-holder"); err != nil {
+			t.Errorf("expected a claim while fn runs: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLease failed: %v", err)
+	}
+	if got != "key-alpha" {
+		t.Errorf("expected 'key-alpha', got %q", got)
+	}
+
+	if _, err := ls.FindByHolder(ctx, "testpool", "test-holder"); !errors.Is(err, lockstore.ErrLeaseNotFound) {
+		t.Errorf("expected slot to be released, got %v", err)
+	}
+}
+
+func TestWithLeaseReleasesOnError(t *testing.T) {
+	c, _ := testClient(t)
+	ctx := context.Background()
+
+	fnErr := errors.New("tests failed")
+	err := c.WithLease(ctx, "testpool", func(env map[string]string) error {
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Fatalf("expected fn's error, got %v", err)
+	}
+
+	// The slot is free again, so the pool's only slot can be claimed
+	lf, err := c.Claim(ctx, "testpool", nil)
+	if err != nil {
+		t.Fatalf("Claim after WithLease failed: %v", err)
+	}
+	if err := c.Release(ctx, lf); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+}
+
+func TestWithLeaseRefusesHeldSlot(t *testing.T) {
+	c, ls := testClient(t)
+	ctx := context.Background()
+
+	err := c.WithLease(ctx, "testpool", func(env map[string]string) error {
+		// A nested call on the same identity would share, then release, the slot
+		err := c.WithLease(ctx, "testpool", func(map[string]string) error {
+			t.Error("expected fn not to run on a held slot")
+			return nil
+		})
+		if !errors.Is(err, claimenv.ErrLeaseHeld) {
+			t.Errorf("expected ErrLeaseHeld, got %v", err)
+		}
+
+		if _, err := ls.FindByHolder(ctx, "testpool", "test-holder"); err != nil {
+			t.Errorf("expected the outer claim to be kept: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLease failed: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package lease
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Version is the current lease file format. Version 1 files hold a single lease
+// at the top level; they are still read, and written back as the current
+// version.
+const Version = 2
+
+var (
+	// ErrNotHeld is returned when the lease file has no lease for the pool.
+	ErrNotHeld = errors.New("no active lease")
+
+	// ErrAmbiguous is returned when no pool is given and the lease file holds
+	// leases in more than one.
+	ErrAmbiguous = errors.New("holding leases in more than one pool")
+)
+
+// File is the lease file: at most one lease per pool, ordered by pool name.
+type File struct {
+	Version int          `json:"version"`
+	Leases  []*LeaseFile `json:"leases"`
+}
+
+// ReadFile reads the lease file at path. A missing file is an empty File.
+//
+// If a lease file key is set (see KeyEnv), the file must have been sealed with
+// it: unsigned files and files whose signature doesn't match are refused.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &File{Version: Version}, nil
+		}
+		return nil, fmt.Errorf("failed to read lease file: %w", err)
+	}
+
+	if data, err = unsealIfKeyed(data, "lease file "+path); err != nil {
+		return nil, err
+	}
+
+	return parseFile(data)
+}
+
+func parseFile(data []byte) (*File, error) {
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse lease file: %w", err)
+	}
+
+	switch {
+	case f.Version == 0:
+		// Version 1 files are a bare lease without a version field
+		var lf LeaseFile
+		if err := json.Unmarshal(data, &lf); err != nil {
+			return nil, fmt.Errorf("failed to parse lease file: %w", err)
+		}
+		f = File{Version: Version}
+		if lf.LeaseID != "" {
+			f.Leases = []*LeaseFile{&lf}
+		}
+	case f.Version > Version:
+		return nil, fmt.Errorf("lease file is version %d, but this claimenv only reads up to version %d", f.Version, Version)
+	}
+
+	f.Version = Version
+	return &f, nil
+}
+
+// WriteFile writes f to path, or removes the file if f holds no leases. If a
+// lease file key is set, the file is encrypted and signed with it.
+func WriteFile(path string, f *File) error {
+	if len(f.Leases) == 0 {
+		return Delete(path)
+	}
+
+	f.Version = Version
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lease file: %w", err)
+	}
+
+	if data, err = sealIfKeyed(data); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the lease in the named pool. If pool is empty, it returns the
+// only lease in the file, or ErrAmbiguous if there are several.
+func (f *File) Get(pool string) (*LeaseFile, error) {
+	if pool != "" {
+		for _, lf := range f.Leases {
+			if lf.Pool == pool {
+				return lf, nil
+			}
+		}
+		return nil, fmt.Errorf("%w in pool %q", ErrNotHeld, pool)
+	}
+
+	switch len(f.Leases) {
+	case 0:
+		return nil, ErrNotHeld
+	case 1:
+		return f.Leases[0], nil
+	default:
+		return nil, fmt.Errorf("%w (%s)", ErrAmbiguous, strings.Join(f.Pools(), ", "))
+	}
+}
+
+// Put adds lf to the file, replacing any lease in the same pool.
+func (f *File) Put(lf *LeaseFile) {
+	f.Remove(lf.Pool)
+	f.Leases = append(f.Leases, lf)
+	sort.Slice(f.Leases, func(i, j int) bool { return f.Leases[i].Pool < f.Leases[j].Pool })
+}
+
+// Remove drops the lease in the named pool, if any.
+func (f *File) Remove(pool string) {
+	kept := f.Leases[:0]
+	for _, lf := range f.Leases {
+		if lf.Pool != pool {
+			kept = append(kept, lf)
+		}
+	}
+	f.Leases = kept
+}
+
+// Pools returns the pools the file holds leases in.
+func (f *File) Pools() []string {
+	pools := make([]string, len(f.Leases))
+	for i, lf := range f.Leases {
+		pools[i] = lf.Pool
+	}
+	return pools
+}
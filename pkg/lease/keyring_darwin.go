@@ -0,0 +1,16 @@
+package lease
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// keyringLookup reads the named entry of the "claimenv" service from the macOS
+// login keychain.
+func keyringLookup(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", "claimenv", "-a", name, "-w").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
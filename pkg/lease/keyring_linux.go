@@ -0,0 +1,16 @@
+package lease
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// keyringLookup reads the named entry of the "claimenv" service from the
+// Secret Service keyring (GNOME Keyring, KWallet) with secret-tool.
+func keyringLookup(name string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", "claimenv", "account", name).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
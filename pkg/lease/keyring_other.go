@@ -0,0 +1,9 @@
+//go:build !darwin && !linux
+
+package lease
+
+import "errors"
+
+func keyringLookup(name string) (string, error) {
+	return "", errors.New("the OS keyring is not supported on this platform; set " + KeyEnv + " instead")
+}
@@ -0,0 +1,73 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LeaseFile is a lease on one slot, as held in the lease file.
+type LeaseFile struct {
+	Pool        string            `json:"pool"`
+	SlotName    string            `json:"slot_name"`
+	LeaseID     string            `json:"lease_id"`
+	Secrets     map[string]string `json:"secrets"`
+	Holder      string            `json:"holder"`
+	ClaimedAt   time.Time         `json:"claimed_at"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Existing is set on a lease returned by a claim when the holder already
+	// held the slot. It isn't stored, and is never set over the http backend.
+	Existing bool `json:"-"`
+}
+
+// Load returns the only lease in the lease file at path. Use ReadFile to pick
+// one of several.
+func Load(path string) (*LeaseFile, error) {
+	f, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lf, err := f.Get("")
+	if errors.Is(err, ErrNotHeld) {
+		return nil, fmt.Errorf("no active lease (file not found: %s)", path)
+	}
+	return lf, err
+}
+
+// Save adds lf to the lease file at path, replacing any lease in the same pool.
+func Save(path string, lf *LeaseFile) error {
+	f, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	f.Put(lf)
+	return WriteFile(path, f)
+}
+
+func Delete(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete lease file: %w", err)
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying lf, for stores that authorize secret access
+// by the lease it's made under.
+func NewContext(ctx context.Context, lf *LeaseFile) context.Context {
+	return context.WithValue(ctx, contextKey{}, lf)
+}
+
+// FromContext returns the lease stored in ctx by NewContext, or nil.
+func FromContext(ctx context.Context) *LeaseFile {
+	lf, _ := ctx.Value(contextKey{}).(*LeaseFile)
+	return lf
+}
@@ -0,0 +1,213 @@
+package lease_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kashuab/claimenv/pkg/lease"
+)
+
+func TestSaveKeepsOneLeasePerPool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claimenv")
+
+	for _, lf := range []*lease.LeaseFile{
+		{Pool: "onboard", SlotName: "alpha", LeaseID: "lease-1"},
+		{Pool: "billing", SlotName: "gamma", LeaseID: "lease-2"},
+		{Pool: "onboard", SlotName: "beta", LeaseID: "lease-3"},
+	} {
+		if err := lease.Save(path, lf); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	f, err := lease.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if got := f.Pools(); len(got) != 2 || got[0] != "billing" || got[1] != "onboard" {
+		t.Fatalf("expected leases in billing and onboard, got %v", got)
+	}
+
+	lf, err := f.Get("onboard")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if lf.LeaseID != "lease-3" {
+		t.Errorf("expected the later onboard lease to replace the first, got %s", lf.LeaseID)
+	}
+
+	if _, err := f.Get(""); !errors.Is(err, lease.ErrAmbiguous) {
+		t.Errorf("expected ErrAmbiguous without a pool, got %v", err)
+	}
+	if _, err := lease.Load(path); !errors.Is(err, lease.ErrAmbiguous) {
+		t.Errorf("expected Load to refuse picking a lease, got %v", err)
+	}
+
+	// Removing the last lease removes the file
+	f.Remove("onboard")
+	f.Remove("billing")
+	if err := lease.WriteFile(path, f); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the empty lease file to be removed, got %v", err)
+	}
+}
+
+func TestReadFileUpgradesSingleLeaseFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claimenv")
+	v1 := `{"pool": "onboard", "slot_name": "alpha", "lease_id": "lease-1", "secrets": {"API_KEY": "alpha-api-key"}}`
+	if err := os.WriteFile(path, []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := lease.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if lf.Pool != "onboard" || lf.LeaseID != "lease-1" || lf.Secrets["API_KEY"] != "alpha-api-key" {
+		t.Errorf("unexpected lease: %+v", lf)
+	}
+
+	// Saving writes the current version
+	if err := lease.Save(path, &lease.LeaseFile{Pool: "billing", LeaseID: "lease-2"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	f, err := lease.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if f.Version != lease.Version || len(f.Leases) != 2 {
+		t.Errorf("expected a version %d file with 2 leases, got version %d with %d", lease.Version, f.Version, len(f.Leases))
+	}
+}
+
+func TestReadFileRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claimenv")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "leases": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := lease.ReadFile(path); err == nil {
+		t.Error("expected an error for a lease file from a newer claimenv")
+	}
+}
+
+func TestSealedLeaseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claimenv")
+	t.Setenv(lease.KeyEnv, "correct horse battery staple")
+
+	lf := &lease.LeaseFile{Pool: "onboard", SlotName: "alpha", LeaseID: "lease-1"}
+	if err := lease.Save(path, lf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "lease-1") {
+		t.Fatalf("sealed lease file contains the lease ID in the clear:\n%s", data)
+	}
+
+	got, err := lease.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got.LeaseID != "lease-1" {
+		t.Errorf("expected lease-1, got %s", got.LeaseID)
+	}
+
+	// Flip a byte of the ciphertext
+	var sealed map[string]any
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, _ := base64.StdEncoding.DecodeString(sealed["data"].(string))
+	ciphertext[0] ^= 1
+	sealed["data"] = base64.StdEncoding.EncodeToString(ciphertext)
+	tampered, _ := json.Marshal(sealed)
+	if err := os.WriteFile(path, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lease.Load(path); !errors.Is(err, lease.ErrTampered) {
+		t.Errorf("expected ErrTampered for a modified file, got %v", err)
+	}
+
+	// The untouched file with the wrong key
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(lease.KeyEnv, "wrong key")
+	if _, err := lease.Load(path); !errors.Is(err, lease.ErrTampered) {
+		t.Errorf("expected ErrTampered with the wrong key, got %v", err)
+	}
+
+	// And with no key at all
+	t.Setenv(lease.KeyEnv, "")
+	if _, err := lease.Load(path); err == nil {
+		t.Error("expected an error reading a sealed file without a key")
+	}
+}
+
+func TestUnsignedLeaseFileRefusedWithKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claimenv")
+	if err := lease.Save(path, &lease.LeaseFile{Pool: "onboard", LeaseID: "lease-1"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	t.Setenv(lease.KeyEnv, "correct horse battery staple")
+	if _, err := lease.Load(path); err == nil {
+		t.Error("expected an unsigned lease file to be refused once a key is set")
+	}
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	lf := &lease.LeaseFile{
+		Pool:      "onboard",
+		SlotName:  "alpha",
+		LeaseID:   "lease-1",
+		ExpiresAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	for _, key := range []string{"", "correct horse battery staple"} {
+		t.Setenv(lease.KeyEnv, key)
+
+		token, err := lease.EncodeToken(lf)
+		if err != nil {
+			t.Fatalf("EncodeToken failed: %v", err)
+		}
+		if key != "" && strings.Contains(token, base64.RawURLEncoding.EncodeToString([]byte("lease-1"))) {
+			t.Errorf("sealed token leaks the lease ID: %s", token)
+		}
+
+		got, err := lease.ParseToken(token)
+		if err != nil {
+			t.Fatalf("ParseToken failed: %v", err)
+		}
+		if got.Pool != lf.Pool || got.SlotName != lf.SlotName || got.LeaseID != lf.LeaseID || !got.ExpiresAt.Equal(lf.ExpiresAt) {
+			t.Errorf("token round trip: got %+v, want %+v", got, lf)
+		}
+	}
+
+	// A token made without a key is refused once one is set
+	t.Setenv(lease.KeyEnv, "")
+	token, err := lease.EncodeToken(lf)
+	if err != nil {
+		t.Fatalf("EncodeToken failed: %v", err)
+	}
+	t.Setenv(lease.KeyEnv, "correct horse battery staple")
+	if _, err := lease.ParseToken(token); err == nil {
+		t.Error("expected an unsigned token to be refused once a key is set")
+	}
+
+	if _, err := lease.ParseToken("not-a-token"); err == nil {
+		t.Error("expected an error for a malformed token")
+	}
+}
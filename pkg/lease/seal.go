@@ -0,0 +1,172 @@
+package lease
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// KeyEnv names the environment variable holding the lease file key.
+	KeyEnv = "CLAIMENV_LEASE_KEY"
+
+	// KeyringEnv names the environment variable holding the name of an OS
+	// keyring entry with the lease file key, used if KeyEnv isn't set.
+	KeyringEnv = "CLAIMENV_LEASE_KEYRING"
+
+	// sealCipher identifies how a sealed lease file is encrypted and signed.
+	sealCipher = "aes-256-ctr+hmac-sha256"
+)
+
+// ErrTampered is returned for a sealed lease file or token whose signature
+// doesn't match, because it was modified or sealed with a different key.
+var ErrTampered = errors.New("signature mismatch: it was modified or sealed with a different key")
+
+// sealedFile is a lease file encrypted and signed with the lease file key. The
+// MAC covers the version, IV and ciphertext, and is checked before decrypting.
+type sealedFile struct {
+	Version int    `json:"version"`
+	Cipher  string `json:"cipher"`
+	IV      []byte `json:"iv"`
+	Data    []byte `json:"data"`
+	MAC     []byte `json:"mac"`
+}
+
+// resolveKey returns the lease file key from KeyEnv or the OS keyring entry
+// named by KeyringEnv, or nil if neither is set.
+func resolveKey() ([]byte, error) {
+	if key := os.Getenv(KeyEnv); key != "" {
+		return []byte(key), nil
+	}
+
+	if name := os.Getenv(KeyringEnv); name != "" {
+		key, err := keyringLookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lease key %q from the OS keyring: %w", name, err)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("lease key %q in the OS keyring is empty", name)
+		}
+		return []byte(key), nil
+	}
+
+	return nil, nil
+}
+
+// subkeys derives separate encryption and MAC keys from the lease file key,
+// so any length of key can be used.
+func subkeys(key []byte) (encKey, macKey []byte) {
+	derive := func(label string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(label))
+		return h.Sum(nil)
+	}
+	return derive("claimenv lease file encryption"), derive("claimenv lease file mac")
+}
+
+func (s *sealedFile) mac(macKey []byte) []byte {
+	h := hmac.New(sha256.New, macKey)
+	fmt.Fprintf(h, "%s:%d:", s.Cipher, s.Version)
+	h.Write(s.IV)
+	h.Write(s.Data)
+	return h.Sum(nil)
+}
+
+// sealIfKeyed seals data if a lease file key is set, and returns it as is
+// otherwise.
+func sealIfKeyed(data []byte) ([]byte, error) {
+	key, err := resolveKey()
+	if err != nil || key == nil {
+		return data, err
+	}
+
+	sealed, err := seal(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal lease: %w", err)
+	}
+	return sealed, nil
+}
+
+// unsealIfKeyed reverses sealIfKeyed for the lease described by what. If a key
+// is set, data must have been sealed with it.
+func unsealIfKeyed(data []byte, what string) ([]byte, error) {
+	key, err := resolveKey()
+	if err != nil {
+		return nil, err
+	}
+
+	switch sealed := isSealed(data); {
+	case sealed && key == nil:
+		return nil, fmt.Errorf("%s is encrypted; set %s or %s to read it", what, KeyEnv, KeyringEnv)
+	case sealed:
+		plaintext, err := unseal(key, data)
+		if err != nil {
+			return nil, fmt.Errorf("refusing to load %s: %w", what, err)
+		}
+		return plaintext, nil
+	case key != nil:
+		return nil, fmt.Errorf("refusing to load %s: it isn't signed, but a lease file key is set", what)
+	}
+	return data, nil
+}
+
+// seal encrypts and signs a marshalled lease.
+func seal(key, plaintext []byte) ([]byte, error) {
+	encKey, macKey := subkeys(key)
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s := sealedFile{Version: Version, Cipher: sealCipher, IV: make([]byte, aes.BlockSize), Data: make([]byte, len(plaintext))}
+	if _, err := rand.Read(s.IV); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+	cipher.NewCTR(block, s.IV).XORKeyStream(s.Data, plaintext)
+	s.MAC = s.mac(macKey)
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// isSealed reports whether data is a sealed lease file.
+func isSealed(data []byte) bool {
+	var probe struct {
+		Cipher string `json:"cipher"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Cipher != ""
+}
+
+// unseal checks the signature of a sealed lease and decrypts it.
+func unseal(key, data []byte) ([]byte, error) {
+	var s sealedFile
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse lease file: %w", err)
+	}
+	if s.Cipher != sealCipher {
+		return nil, fmt.Errorf("lease file is sealed with unsupported cipher %q", s.Cipher)
+	}
+
+	encKey, macKey := subkeys(key)
+	if !hmac.Equal(s.MAC, s.mac(macKey)) {
+		return nil, ErrTampered
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.IV) != aes.BlockSize {
+		return nil, ErrTampered
+	}
+
+	plaintext := make([]byte, len(s.Data))
+	cipher.NewCTR(block, s.IV).XORKeyStream(plaintext, s.Data)
+	return plaintext, nil
+}
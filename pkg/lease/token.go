@@ -0,0 +1,78 @@
+package lease
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// TokenEnv names the environment variable that carries a lease token in
+	// place of a lease file.
+	TokenEnv = "CLAIMENV_LEASE"
+
+	// tokenPrefix marks the token format, so it can change later.
+	tokenPrefix = "cl1."
+)
+
+// token is the payload of a lease token, with short keys to keep it compact.
+type token struct {
+	Pool      string `json:"p"`
+	SlotName  string `json:"s"`
+	LeaseID   string `json:"l"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// EncodeToken returns a compact, URL-safe token for lf carrying its pool, slot,
+// lease ID and expiry. If a lease file key is set, the token is encrypted and
+// signed with it like a lease file.
+func EncodeToken(lf *LeaseFile) (string, error) {
+	data, err := json.Marshal(token{
+		Pool:      lf.Pool,
+		SlotName:  lf.SlotName,
+		LeaseID:   lf.LeaseID,
+		ExpiresAt: lf.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lease token: %w", err)
+	}
+
+	if data, err = sealIfKeyed(data); err != nil {
+		return "", err
+	}
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ParseToken decodes a token from EncodeToken. The returned lease only has the
+// token's fields set; the rest must be looked up from the lock store.
+func ParseToken(s string) (*LeaseFile, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(s), tokenPrefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid lease token: expected a token from claimenv claim --print-token")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease token: %w", err)
+	}
+	if data, err = unsealIfKeyed(data, "lease token"); err != nil {
+		return nil, err
+	}
+
+	var t token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid lease token: %w", err)
+	}
+	if t.Pool == "" || t.LeaseID == "" {
+		return nil, fmt.Errorf("invalid lease token: missing pool or lease ID")
+	}
+
+	return &LeaseFile{
+		Pool:      t.Pool,
+		SlotName:  t.SlotName,
+		LeaseID:   t.LeaseID,
+		ExpiresAt: time.Unix(t.ExpiresAt, 0),
+	}, nil
+}
@@ -0,0 +1,798 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/Kashuab/claimenv/internal/audit"
+	"github.com/Kashuab/claimenv/internal/tracing"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer("github.com/Kashuab/claimenv/pkg/lockstore/firestore")
+
+// Store implements lockstore.LockStore using Google Cloud Firestore.
+type Store struct {
+	client     *firestore.Client
+	collection string
+}
+
+// slotDoc is the Firestore document schema for a slot.
+type slotDoc struct {
+	Pool        string    `firestore:"pool"`
+	SlotName    string    `firestore:"slot_name"`
+	LeaseID     string    `firestore:"lease_id"`
+	Holder      string    `firestore:"holder"`
+	ClaimedAt   time.Time `firestore:"claimed_at"`
+	ExpiresAt   time.Time `firestore:"expires_at"`
+	State       string    `firestore:"state"`
+	StateReason string    `firestore:"state_reason"`
+	ReleasedAt  time.Time `firestore:"released_at"`
+
+	Annotations  map[string]string `firestore:"annotations"`
+	Reservations []reservationDoc  `firestore:"reservations"`
+}
+
+// reservationDoc is a reservation embedded in its slot's document, so claims
+// and reservations are checked within the same transaction.
+type reservationDoc struct {
+	ID     string    `firestore:"id"`
+	Holder string    `firestore:"holder"`
+	From   time.Time `firestore:"from"`
+	Until  time.Time `firestore:"until"`
+}
+
+// claim returns the lease recorded in the slot document.
+func (sd *slotDoc) claim() *lockstore.Claim {
+	return &lockstore.Claim{
+		Pool:      sd.Pool,
+		SlotName:  sd.SlotName,
+		LeaseID:   sd.LeaseID,
+		Holder:    sd.Holder,
+		ClaimedAt: sd.ClaimedAt,
+		ExpiresAt: sd.ExpiresAt,
+
+		Annotations: sd.Annotations,
+	}
+}
+
+// reservations returns the slot's reservations that haven't ended yet.
+func (sd *slotDoc) reservations() []lockstore.Reservation {
+	var rs []lockstore.Reservation
+	for _, r := range sd.Reservations {
+		rs = append(rs, lockstore.Reservation{
+			ID:       r.ID,
+			Pool:     sd.Pool,
+			SlotName: sd.SlotName,
+			Holder:   r.Holder,
+			From:     r.From,
+			Until:    r.Until,
+		})
+	}
+	return lockstore.PruneReservations(rs, time.Now())
+}
+
+// setReservations replaces the slot's stored reservations.
+func (sd *slotDoc) setReservations(rs []lockstore.Reservation) {
+	sd.Reservations = nil
+	for _, r := range rs {
+		sd.Reservations = append(sd.Reservations, reservationDoc{
+			ID:     r.ID,
+			Holder: r.Holder,
+			From:   r.From,
+			Until:  r.Until,
+		})
+	}
+}
+
+// slotState returns the administrative state of the slot, treating documents
+// written before slot states existed as active.
+func (sd *slotDoc) slotState() lockstore.SlotState {
+	if sd.State == "" {
+		return lockstore.SlotActive
+	}
+	return lockstore.SlotState(sd.State)
+}
+
+// eventDoc is the Firestore document schema for a slot history entry, stored
+// in the "history" subcollection of the slot's document.
+type eventDoc struct {
+	Pool        string            `firestore:"pool"`
+	SlotName    string            `firestore:"slot_name"`
+	Kind        string            `firestore:"kind"`
+	Holder      string            `firestore:"holder"`
+	LeaseID     string            `firestore:"lease_id"`
+	At          time.Time         `firestore:"at"`
+	Annotations map[string]string `firestore:"annotations"`
+}
+
+func New(ctx context.Context, project, collection string) (*Store, error) {
+	client, err := firestore.NewClient(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firestore client: %w", err)
+	}
+	return &Store{client: client, collection: collection}, nil
+}
+
+func (s *Store) docID(pool string, slotName string) string {
+	return fmt.Sprintf("%s-%s", pool, slotName)
+}
+
+func (s *Store) docRef(pool string, slotName string) *firestore.DocumentRef {
+	return s.client.Collection(s.collection).Doc(s.docID(pool, slotName))
+}
+
+// runTransaction runs fn in a Firestore transaction. Every attempt is recorded
+// as an event on the current span, so contention retries show up in traces.
+func (s *Store) runTransaction(ctx context.Context, fn func(context.Context, *firestore.Transaction) error) error {
+	span := trace.SpanFromContext(ctx)
+	attempts := 0
+
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		attempts++
+		span.AddEvent("transaction attempt", trace.WithAttributes(attribute.Int("attempt", attempts)))
+		return fn(ctx, tx)
+	})
+
+	span.SetAttributes(attribute.Int("claimenv.firestore.attempts", attempts))
+	return err
+}
+
+// getSlots reads the documents of all named slots within the transaction.
+// Slots without a document yet are returned as empty, free documents.
+func (s *Store) getSlots(tx *firestore.Transaction, pool string, slotNames []string) ([]slotDoc, error) {
+	docs := make([]slotDoc, len(slotNames))
+	for i, name := range slotNames {
+		docs[i] = slotDoc{Pool: pool, SlotName: name}
+
+		doc, err := tx.Get(s.docRef(pool, name))
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read slot %q: %w", name, err)
+		}
+
+		if err := doc.DataTo(&docs[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse slot %q: %w", name, err)
+		}
+	}
+	return docs, nil
+}
+
+func (s *Store) Claim(ctx context.Context, pool string, slotNames []string, holder string, ttl time.Duration, opts lockstore.ClaimOptions) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Claim", trace.WithAttributes(tracing.PoolKey.String(pool), tracing.HolderKey.String(holder)))
+	defer tracing.End(span, &err)
+
+	var result *lockstore.Claim
+
+	err = s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+
+		docs, err := s.getSlots(tx, pool, slotNames)
+		if err != nil {
+			return err
+		}
+
+		// First pass: check if this holder already has an active claim,
+		// collecting the holders of all active claims for quota checks
+		var active []string
+		for _, sd := range docs {
+			if sd.LeaseID != "" && now.Before(sd.ExpiresAt) {
+				active = append(active, sd.Holder)
+			}
+
+			if sd.slotState() == lockstore.SlotQuarantined {
+				continue
+			}
+
+			if sd.Holder == holder && now.Before(sd.ExpiresAt) {
+				result = sd.claim()
+				result.Existing = true
+				return nil
+			}
+		}
+
+		// A holder claiming during its own reservation gets the reserved slot
+		for i := range docs {
+			sd := &docs[i]
+			if sd.slotState() == lockstore.SlotQuarantined {
+				continue
+			}
+			if lockstore.ActiveReservation(sd.reservations(), holder, now) == nil {
+				continue
+			}
+			if sd.LeaseID == "" {
+				result, err = s.claimSlot(tx, sd, holder, now, ttl, opts.Annotations)
+				return err
+			}
+		}
+
+		if err := lockstore.CheckQuotas(opts.Quotas, holder, active); err != nil {
+			return err
+		}
+
+		// Second pass: find a free slot
+		for i := range docs {
+			sd := &docs[i]
+			st := sd.slotState()
+			if st == lockstore.SlotDraining || st == lockstore.SlotQuarantined {
+				continue
+			}
+			if lockstore.ConflictingReservation(sd.reservations(), holder, now, now.Add(ttl)) != nil {
+				continue
+			}
+
+			if sd.LeaseID == "" && !now.Before(sd.ReleasedAt.Add(opts.Cooldown)) {
+				result, err = s.claimSlot(tx, sd, holder, now, ttl, opts.Annotations)
+				return err
+			}
+		}
+
+		return lockstore.ErrPoolExhausted
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// claimSlot writes a new claim for holder into the slot document, keeping the
+// slot's administrative state and reservations.
+func (s *Store) claimSlot(tx *firestore.Transaction, sd *slotDoc, holder string, now time.Time, ttl time.Duration, annotations map[string]string) (*lockstore.Claim, error) {
+	sd.LeaseID = uuid.New().String()
+	sd.Holder = holder
+	sd.ClaimedAt = now
+	sd.ExpiresAt = now.Add(ttl)
+	sd.Annotations = annotations
+	sd.setReservations(sd.reservations())
+
+	if err := tx.Set(s.docRef(sd.Pool, sd.SlotName), *sd); err != nil {
+		return nil, fmt.Errorf("failed to write slot %q: %w", sd.SlotName, err)
+	}
+
+	claim := sd.claim()
+	if err := s.recordEvent(tx, lockstore.NewEvent(lockstore.EventClaim, claim, now)); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+func (s *Store) Release(ctx context.Context, pool string, leaseID string) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Release", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	return s.release(ctx, pool, leaseID, lockstore.EventRelease)
+}
+
+func (s *Store) Revoke(ctx context.Context, pool string, leaseID string) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Revoke", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	return s.release(ctx, pool, leaseID, lockstore.EventRevoke)
+}
+
+// release frees the slot holding leaseID, recording an event of the given kind.
+func (s *Store) release(ctx context.Context, pool string, leaseID string, kind lockstore.EventKind) error {
+	return s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		iter := tx.Documents(s.client.Collection(s.collection).Where("pool", "==", pool).Where("lease_id", "==", leaseID))
+		docs, err := iter.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to query for lease: %w", err)
+		}
+
+		if len(docs) == 0 {
+			return lockstore.ErrLeaseNotFound
+		}
+
+		var sd slotDoc
+		if err := docs[0].DataTo(&sd); err != nil {
+			return fmt.Errorf("failed to parse slot: %w", err)
+		}
+
+		now := time.Now()
+		return s.freeSlot(tx, docs[0].Ref, &sd, lockstore.NewEvent(kind, sd.claim(), now), now)
+	})
+}
+
+func (s *Store) ReleaseByHolder(ctx context.Context, pool string, holder string) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.ReleaseByHolder", trace.WithAttributes(tracing.PoolKey.String(pool), tracing.HolderKey.String(holder)))
+	defer tracing.End(span, &err)
+
+	return s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+
+		iter := tx.Documents(s.client.Collection(s.collection).Where("pool", "==", pool).Where("holder", "==", holder))
+		docs, err := iter.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to query for holder: %w", err)
+		}
+
+		for _, doc := range docs {
+			var sd slotDoc
+			if err := doc.DataTo(&sd); err != nil {
+				return fmt.Errorf("failed to parse slot: %w", err)
+			}
+
+			if now.Before(sd.ExpiresAt) {
+				return s.freeSlot(tx, doc.Ref, &sd, lockstore.NewEvent(lockstore.EventRelease, sd.claim(), now), now)
+			}
+		}
+
+		return lockstore.ErrLeaseNotFound
+	})
+}
+
+// freeSlot clears the lease from a slot document and records ev in the slot's history.
+func (s *Store) freeSlot(tx *firestore.Transaction, ref *firestore.DocumentRef, sd *slotDoc, ev lockstore.Event, releasedAt time.Time) error {
+	if err := tx.Update(ref, []firestore.Update{
+		{Path: "lease_id", Value: ""},
+		{Path: "holder", Value: ""},
+		{Path: "annotations", Value: firestore.Delete},
+		{Path: "released_at", Value: releasedAt},
+	}); err != nil {
+		return err
+	}
+	return s.recordEvent(tx, ev)
+}
+
+func (s *Store) FindByHolder(ctx context.Context, pool string, holder string) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.FindByHolder", trace.WithAttributes(tracing.PoolKey.String(pool), tracing.HolderKey.String(holder)))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+
+	iter := s.client.Collection(s.collection).Where("pool", "==", pool).Where("holder", "==", holder).Documents(ctx)
+	docs, err := iter.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query for holder: %w", err)
+	}
+
+	for _, doc := range docs {
+		var sd slotDoc
+		if err := doc.DataTo(&sd); err != nil {
+			return nil, fmt.Errorf("failed to parse slot: %w", err)
+		}
+
+		if now.Before(sd.ExpiresAt) {
+			return sd.claim(), nil
+		}
+	}
+
+	return nil, lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) Renew(ctx context.Context, pool string, leaseID string, ttl time.Duration, maxLease time.Duration) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Renew", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	var result *lockstore.Claim
+
+	err = s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+
+		iter := tx.Documents(s.client.Collection(s.collection).Where("pool", "==", pool).Where("lease_id", "==", leaseID))
+		docs, err := iter.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to query for lease: %w", err)
+		}
+
+		if len(docs) == 0 {
+			return lockstore.ErrLeaseNotFound
+		}
+
+		var sd slotDoc
+		if err := docs[0].DataTo(&sd); err != nil {
+			return fmt.Errorf("failed to parse slot: %w", err)
+		}
+
+		result, err = renewClaim(&sd, now, ttl, maxLease)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Update(docs[0].Ref, []firestore.Update{
+			{Path: "expires_at", Value: result.ExpiresAt},
+		}); err != nil {
+			return err
+		}
+
+		return s.recordEvent(tx, lockstore.NewEvent(lockstore.EventRenew, result, now))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Store) Reap(ctx context.Context, pool string, slotNames []string) (_ []lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Reap", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	var reaped []lockstore.Claim
+
+	err = s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		reaped = nil
+
+		// Firestore transactions require all reads before any writes
+		var expired []*firestore.DocumentRef
+		var expiredDocs []slotDoc
+		for _, name := range slotNames {
+			ref := s.docRef(pool, name)
+			doc, err := tx.Get(ref)
+			if err != nil {
+				if status.Code(err) == codes.NotFound {
+					continue
+				}
+				return fmt.Errorf("failed to read slot %q: %w", name, err)
+			}
+
+			var sd slotDoc
+			if err := doc.DataTo(&sd); err != nil {
+				return fmt.Errorf("failed to parse slot %q: %w", name, err)
+			}
+
+			if sd.LeaseID != "" && now.After(sd.ExpiresAt) {
+				expired = append(expired, ref)
+				expiredDocs = append(expiredDocs, sd)
+				reaped = append(reaped, *sd.claim())
+			}
+		}
+
+		for i, ref := range expired {
+			sd := &expiredDocs[i]
+			// The cooldown runs from when the lease lapsed, not from the reap
+			if err := s.freeSlot(tx, ref, sd, lockstore.NewEvent(lockstore.EventExpire, sd.claim(), sd.ExpiresAt), sd.ExpiresAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return reaped, nil
+}
+
+func (s *Store) Reserve(ctx context.Context, pool string, slotNames []string, holder string, from, until time.Time) (_ *lockstore.Reservation, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Reserve", trace.WithAttributes(tracing.PoolKey.String(pool), tracing.HolderKey.String(holder)))
+	defer tracing.End(span, &err)
+
+	var result *lockstore.Reservation
+
+	err = s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := s.getSlots(tx, pool, slotNames)
+		if err != nil {
+			return err
+		}
+
+		for i := range docs {
+			sd := &docs[i]
+			if sd.slotState() != lockstore.SlotActive {
+				continue
+			}
+			if sd.LeaseID != "" && sd.Holder != holder && sd.ExpiresAt.After(from) {
+				continue
+			}
+			rs := sd.reservations()
+			// Any overlapping reservation conflicts, including the holder's own
+			if lockstore.ConflictingReservation(rs, "", from, until) != nil {
+				continue
+			}
+
+			r := lockstore.Reservation{
+				ID:       uuid.New().String(),
+				Pool:     pool,
+				SlotName: sd.SlotName,
+				Holder:   holder,
+				From:     from,
+				Until:    until,
+			}
+			sd.setReservations(append(rs, r))
+
+			if err := tx.Set(s.docRef(pool, sd.SlotName), *sd); err != nil {
+				return fmt.Errorf("failed to write slot %q: %w", sd.SlotName, err)
+			}
+
+			result = &r
+			return nil
+		}
+
+		return lockstore.ErrReservationConflict
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Store) CancelReservation(ctx context.Context, pool string, reservationID string) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.CancelReservation", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	return s.runTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		iter := tx.Documents(s.client.Collection(s.collection).Where("pool", "==", pool))
+		docs, err := iter.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to query pool: %w", err)
+		}
+
+		for _, doc := range docs {
+			var sd slotDoc
+			if err := doc.DataTo(&sd); err != nil {
+				return fmt.Errorf("failed to parse slot: %w", err)
+			}
+
+			for i, r := range sd.Reservations {
+				if r.ID == reservationID {
+					kept := append(sd.Reservations[:i:i], sd.Reservations[i+1:]...)
+					return tx.Update(doc.Ref, []firestore.Update{
+						{Path: "reservations", Value: kept},
+					})
+				}
+			}
+		}
+
+		return lockstore.ErrReservationNotFound
+	})
+}
+
+// recordEvent appends ev to the history of its slot within the transaction.
+func (s *Store) recordEvent(tx *firestore.Transaction, ev lockstore.Event) error {
+	ref := s.docRef(ev.Pool, ev.SlotName).Collection("history").NewDoc()
+	if err := tx.Create(ref, eventDoc{
+		Pool:        ev.Pool,
+		SlotName:    ev.SlotName,
+		Kind:        string(ev.Kind),
+		Holder:      ev.Holder,
+		LeaseID:     ev.LeaseID,
+		At:          ev.At,
+		Annotations: ev.Annotations,
+	}); err != nil {
+		return fmt.Errorf("failed to record %s event for slot %q: %w", ev.Kind, ev.SlotName, err)
+	}
+	return nil
+}
+
+func (s *Store) History(ctx context.Context, pool string, slotNames []string, since time.Time) (_ []lockstore.Event, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.History", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	var events []lockstore.Event
+
+	for _, name := range slotNames {
+		docs, err := s.docRef(pool, name).Collection("history").
+			Where("at", ">=", since).OrderBy("at", firestore.Asc).
+			Documents(ctx).GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of slot %q: %w", name, err)
+		}
+
+		for _, doc := range docs {
+			var ed eventDoc
+			if err := doc.DataTo(&ed); err != nil {
+				return nil, fmt.Errorf("failed to parse history of slot %q: %w", name, err)
+			}
+			events = append(events, lockstore.Event{
+				Pool:        ed.Pool,
+				SlotName:    ed.SlotName,
+				Kind:        lockstore.EventKind(ed.Kind),
+				Holder:      ed.Holder,
+				LeaseID:     ed.LeaseID,
+				At:          ed.At,
+				Annotations: ed.Annotations,
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events, nil
+}
+
+func (s *Store) Status(ctx context.Context, pool string, slotNames []string) (_ []lockstore.SlotStatus, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.Status", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+	statuses := make([]lockstore.SlotStatus, len(slotNames))
+
+	for i, name := range slotNames {
+		doc, err := s.docRef(pool, name).Get(ctx)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				statuses[i] = slotStatus(name, nil, now)
+				continue
+			}
+			return nil, fmt.Errorf("failed to read slot %q: %w", name, err)
+		}
+
+		var sd slotDoc
+		if err := doc.DataTo(&sd); err != nil {
+			return nil, fmt.Errorf("failed to parse slot %q: %w", name, err)
+		}
+		statuses[i] = slotStatus(name, &sd, now)
+	}
+
+	return statuses, nil
+}
+
+// slotStatus returns the status of the named slot at now, given its document
+// or nil if it has none yet.
+func slotStatus(name string, sd *slotDoc, now time.Time) lockstore.SlotStatus {
+	st := lockstore.SlotStatus{SlotName: name, State: lockstore.SlotActive}
+	if sd == nil {
+		return st
+	}
+
+	st.State = sd.slotState()
+	st.Reason = sd.StateReason
+	st.Reservations = sd.reservations()
+
+	if sd.LeaseID == "" && !sd.ReleasedAt.IsZero() {
+		releasedAt := sd.ReleasedAt
+		st.ReleasedAt = &releasedAt
+	}
+
+	if sd.LeaseID != "" {
+		st.Claimed = now.Before(sd.ExpiresAt)
+		st.Expired = !st.Claimed
+		st.Claim = sd.claim()
+	}
+	return st
+}
+
+// Watch implements lockstore.Watcher with a snapshot listener on the pool's
+// slot documents, so each change costs a read of the changed documents only.
+func (s *Store) Watch(ctx context.Context, pool string, slotNames []string, fn func([]lockstore.SlotStatus) error) error {
+	it := s.client.Collection(s.collection).Where("pool", "==", pool).Snapshots(ctx)
+	defer it.Stop()
+
+	for {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to watch pool %q: %w", pool, err)
+		}
+
+		docs, err := snap.Documents.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to read pool %q: %w", pool, err)
+		}
+
+		bySlot := make(map[string]*slotDoc, len(docs))
+		for _, doc := range docs {
+			var sd slotDoc
+			if err := doc.DataTo(&sd); err != nil {
+				return fmt.Errorf("failed to parse slot document %q: %w", doc.Ref.ID, err)
+			}
+			bySlot[sd.SlotName] = &sd
+		}
+
+		now := time.Now()
+		statuses := make([]lockstore.SlotStatus, len(slotNames))
+		for i, name := range slotNames {
+			statuses[i] = slotStatus(name, bySlot[name], now)
+		}
+
+		if err := fn(statuses); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Store) SetSlotState(ctx context.Context, pool string, slotName string, state lockstore.SlotState, reason string) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.SetSlotState", trace.WithAttributes(tracing.PoolKey.String(pool), tracing.SlotKey.String(slotName)))
+	defer tracing.End(span, &err)
+
+	if state != lockstore.SlotQuarantined {
+		reason = ""
+	}
+
+	_, err = s.docRef(pool, slotName).Set(ctx, map[string]interface{}{
+		"pool":         pool,
+		"slot_name":    slotName,
+		"state":        string(state),
+		"state_reason": reason,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("failed to update state of slot %q: %w", slotName, err)
+	}
+	return nil
+}
+
+func (s *Store) ValidateLease(ctx context.Context, pool string, leaseID string) (_ *lockstore.Claim, err error) {
+	ctx, span := tracer.Start(ctx, "firestore.ValidateLease", trace.WithAttributes(tracing.PoolKey.String(pool)))
+	defer tracing.End(span, &err)
+
+	now := time.Now()
+
+	iter := s.client.Collection(s.collection).Where("pool", "==", pool).Where("lease_id", "==", leaseID).Documents(ctx)
+	docs, err := iter.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query for lease: %w", err)
+	}
+
+	if len(docs) == 0 {
+		return nil, lockstore.ErrLeaseNotFound
+	}
+
+	var sd slotDoc
+	if err := docs[0].DataTo(&sd); err != nil {
+		return nil, fmt.Errorf("failed to parse slot: %w", err)
+	}
+
+	return validClaim(&sd, now)
+}
+
+// validClaim returns the slot's claim, or ErrLeaseExpired if it lapsed
+// before now.
+func validClaim(sd *slotDoc, now time.Time) (*lockstore.Claim, error) {
+	if now.After(sd.ExpiresAt) {
+		return nil, lockstore.ErrLeaseExpired
+	}
+	return sd.claim(), nil
+}
+
+// renewClaim returns the slot's claim with its expiry extended at now by ttl,
+// capped by maxLease and the slot's reservations.
+func renewClaim(sd *slotDoc, now time.Time, ttl, maxLease time.Duration) (*lockstore.Claim, error) {
+	claim, err := validClaim(sd, now)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := lockstore.RenewExpiry(claim, now, ttl, maxLease)
+	if err != nil {
+		return nil, err
+	}
+	expiry, err = lockstore.CapForReservations(sd.reservations(), sd.Holder, now, sd.ExpiresAt, expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	claim.ExpiresAt = expiry
+	return claim, nil
+}
+
+// AppendAudit stores ev in the "{collection}-audit" collection. Documents are
+// only ever created, never updated.
+func (s *Store) AppendAudit(ctx context.Context, ev audit.Event) (err error) {
+	ctx, span := tracer.Start(ctx, "firestore.AppendAudit", trace.WithAttributes(tracing.PoolKey.String(ev.Pool), tracing.SlotKey.String(ev.Slot)))
+	defer tracing.End(span, &err)
+
+	_, err = s.client.Collection(s.collection+"-audit").NewDoc().Create(ctx, map[string]interface{}{
+		"time":     ev.Time,
+		"action":   string(ev.Action),
+		"pool":     ev.Pool,
+		"slot":     ev.Slot,
+		"key":      ev.Key,
+		"lease_id": ev.LeaseID,
+		"holder":   ev.Holder,
+		"identity": ev.Identity,
+		"actor":    ev.Actor,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,61 @@
+package firestore
+
+import (
+	"testing"
+	"time"
+)
+
+// annotatedSlot returns a slot document claimed at now with a branch
+// annotation.
+func annotatedSlot(now time.Time) *slotDoc {
+	return &slotDoc{
+		Pool:      "testpool",
+		SlotName:  "alpha",
+		LeaseID:   "lease-1",
+		Holder:    "test-holder",
+		ClaimedAt: now.Add(-time.Minute),
+		ExpiresAt: now.Add(time.Hour),
+
+		Annotations: map[string]string{"branch": "feature/checkout"},
+	}
+}
+
+func TestSlotStatusIncludesAnnotations(t *testing.T) {
+	now := time.Now()
+	sd := annotatedSlot(now)
+
+	st := slotStatus("alpha", sd, now)
+	if !st.Claimed || st.Claim == nil {
+		t.Fatalf("expected slot 'alpha' to be claimed, got %+v", st)
+	}
+	if st.Claim.Annotations["branch"] != "feature/checkout" {
+		t.Errorf("expected the branch annotation in the status, got %v", st.Claim.Annotations)
+	}
+}
+
+func TestRenewClaimIncludesAnnotations(t *testing.T) {
+	now := time.Now()
+
+	claim, err := renewClaim(annotatedSlot(now), now, 2*time.Hour, 0)
+	if err != nil {
+		t.Fatalf("renewClaim failed: %v", err)
+	}
+	if !claim.ExpiresAt.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("expected the claim to expire in 2h, got %v", claim.ExpiresAt)
+	}
+	if claim.Annotations["branch"] != "feature/checkout" {
+		t.Errorf("expected the branch annotation on the renewed claim, got %v", claim.Annotations)
+	}
+}
+
+func TestValidClaimIncludesAnnotations(t *testing.T) {
+	now := time.Now()
+
+	claim, err := validClaim(annotatedSlot(now), now)
+	if err != nil {
+		t.Fatalf("validClaim failed: %v", err)
+	}
+	if claim.Annotations["branch"] != "feature/checkout" {
+		t.Errorf("expected the branch annotation on the validated claim, got %v", claim.Annotations)
+	}
+}
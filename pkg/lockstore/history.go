@@ -0,0 +1,42 @@
+package lockstore
+
+import "time"
+
+// EventKind identifies what happened to a slot's lease.
+type EventKind string
+
+const (
+	EventClaim   EventKind = "claim"
+	EventRenew   EventKind = "renew"
+	EventRelease EventKind = "release"
+	EventExpire  EventKind = "expire"
+	EventRevoke  EventKind = "revoke"
+)
+
+// Event is an entry in a slot's claim history. For expire events, At is the
+// time the lease lapsed rather than the time it was cleaned up. The lease ID is
+// left out of JSON, as the lease may still be active.
+type Event struct {
+	Pool     string    `json:"pool"`
+	SlotName string    `json:"slot_name"`
+	Kind     EventKind `json:"kind"`
+	Holder   string    `json:"holder"`
+	LeaseID  string    `json:"-"`
+	At       time.Time `json:"at"`
+
+	// Annotations are the claim's annotations at the time of the event.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NewEvent returns an event of the given kind for claim.
+func NewEvent(kind EventKind, claim *Claim, at time.Time) Event {
+	return Event{
+		Pool:        claim.Pool,
+		SlotName:    claim.SlotName,
+		Kind:        kind,
+		Holder:      claim.Holder,
+		LeaseID:     claim.LeaseID,
+		At:          at,
+		Annotations: claim.Annotations,
+	}
+}
@@ -0,0 +1,128 @@
+// Package httpstore implements lockstore.LockStore by talking to a
+// "claimenv serve" instance. The server applies its own pool config, so the
+// slot names, TTL and claim policy passed by the engine are ignored.
+package httpstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/pkg/lease"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+)
+
+// Store implements lockstore.LockStore against a claimenv server. Claim,
+// renew, release and status go through the server's engine, so its hooks and
+// resets run there. Administrative operations are not available remotely.
+type Store struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Store {
+	return &Store{client: client}
+}
+
+func unsupported(op string) error {
+	return fmt.Errorf("%s is not available through the http lock backend: %w", op, errors.ErrUnsupported)
+}
+
+func claimFromLease(lf *lease.LeaseFile) *lockstore.Claim {
+	return &lockstore.Claim{
+		Pool:        lf.Pool,
+		SlotName:    lf.SlotName,
+		LeaseID:     lf.LeaseID,
+		Holder:      lf.Holder,
+		ClaimedAt:   lf.ClaimedAt,
+		ExpiresAt:   lf.ExpiresAt,
+		Annotations: lf.Annotations,
+	}
+}
+
+func (s *Store) Claim(ctx context.Context, pool string, slotNames []string, holder string, ttl time.Duration, opts lockstore.ClaimOptions) (*lockstore.Claim, error) {
+	var lf lease.LeaseFile
+	req := api.ClaimRequest{Holder: holder, Annotations: opts.Annotations}
+	if err := s.client.Do(ctx, http.MethodPost, api.PoolPath(pool, "claims"), "", req, &lf); err != nil {
+		return nil, err
+	}
+	return claimFromLease(&lf), nil
+}
+
+func (s *Store) Release(ctx context.Context, pool string, leaseID string) error {
+	return s.client.Do(ctx, http.MethodDelete, api.PoolPath(pool, "lease"), leaseID, nil, nil)
+}
+
+func (s *Store) Revoke(ctx context.Context, pool string, leaseID string) error {
+	return unsupported("revoke")
+}
+
+func (s *Store) ReleaseByHolder(ctx context.Context, pool string, holder string) error {
+	claim, err := s.FindByHolder(ctx, pool, holder)
+	if err != nil {
+		return err
+	}
+	return s.Release(ctx, pool, claim.LeaseID)
+}
+
+// FindByHolder returns the claim of holder, which the server only looks up for
+// the client's own identity.
+func (s *Store) FindByHolder(ctx context.Context, pool string, holder string) (*lockstore.Claim, error) {
+	var lf lease.LeaseFile
+	path := api.PoolPath(pool, "claims") + "?holder=" + url.QueryEscape(holder)
+	if err := s.client.Do(ctx, http.MethodGet, path, "", nil, &lf); err != nil {
+		return nil, err
+	}
+	return claimFromLease(&lf), nil
+}
+
+func (s *Store) Renew(ctx context.Context, pool string, leaseID string, ttl time.Duration, maxLease time.Duration) (*lockstore.Claim, error) {
+	var lf lease.LeaseFile
+	if err := s.client.Do(ctx, http.MethodPost, api.PoolPath(pool, "lease", "renew"), leaseID, nil, &lf); err != nil {
+		return nil, err
+	}
+	return claimFromLease(&lf), nil
+}
+
+func (s *Store) Reap(ctx context.Context, pool string, slotNames []string) ([]lockstore.Claim, error) {
+	return nil, unsupported("reap")
+}
+
+func (s *Store) Reserve(ctx context.Context, pool string, slotNames []string, holder string, from, until time.Time) (*lockstore.Reservation, error) {
+	return nil, unsupported("reserve")
+}
+
+func (s *Store) CancelReservation(ctx context.Context, pool string, reservationID string) error {
+	return unsupported("unreserve")
+}
+
+func (s *Store) History(ctx context.Context, pool string, slotNames []string, since time.Time) ([]lockstore.Event, error) {
+	return nil, unsupported("history")
+}
+
+func (s *Store) Status(ctx context.Context, pool string, slotNames []string) ([]lockstore.SlotStatus, error) {
+	var statuses []lockstore.SlotStatus
+	if err := s.client.Do(ctx, http.MethodGet, api.PoolPath(pool, "status"), "", nil, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (s *Store) SetSlotState(ctx context.Context, pool string, slotName string, state lockstore.SlotState, reason string) error {
+	return unsupported("changing slot states")
+}
+
+func (s *Store) ValidateLease(ctx context.Context, pool string, leaseID string) (*lockstore.Claim, error) {
+	var lf lease.LeaseFile
+	if err := s.client.Do(ctx, http.MethodGet, api.PoolPath(pool, "lease"), leaseID, nil, &lf); err != nil {
+		return nil, err
+	}
+	return claimFromLease(&lf), nil
+}
+
+func (s *Store) Close() error {
+	return nil
+}
@@ -0,0 +1,246 @@
+package lockstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrPoolExhausted = errors.New("claimenv: all slots in pool are currently claimed")
+	ErrLeaseNotFound = errors.New("claimenv: lease not found")
+	ErrLeaseExpired  = errors.New("claimenv: lease has expired")
+
+	ErrMaxLeaseExceeded = errors.New("claimenv: lease has reached its maximum lifetime")
+	ErrQuotaExceeded    = errors.New("claimenv: claim quota exceeded")
+)
+
+// SlotState is the administrative state of a slot, independent of whether it is
+// currently claimed.
+type SlotState string
+
+const (
+	// SlotActive slots are handed out by Claim as usual.
+	SlotActive SlotState = "active"
+	// SlotDraining slots keep their current lease but accept no new claims.
+	SlotDraining SlotState = "draining"
+	// SlotQuarantined slots are never claimable until restored.
+	SlotQuarantined SlotState = "quarantined"
+)
+
+// Claim represents an active lease on a slot. Its LeaseID is a bearer
+// credential: anyone who knows it can read and write the slot's values, so
+// it's only handed to the holder and kept out of logs, status and history.
+type Claim struct {
+	Pool      string    `json:"pool"`
+	SlotName  string    `json:"slot_name"`
+	LeaseID   string    `json:"lease_id"`
+	Holder    string    `json:"holder"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Annotations is holder-supplied context such as an MR URL or branch.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Existing is set on a claim returned by LockStore.Claim when the holder
+	// already held it, rather than acquiring it anew. It isn't stored. The
+	// http store can't tell and leaves it unset; its server runs the claim
+	// hooks itself.
+	Existing bool `json:"-"`
+}
+
+// ClaimOptions carries the pool policy applied by LockStore.Claim, and the
+// holder's annotations stored on a new claim.
+type ClaimOptions struct {
+	// Cooldown is how long a slot stays unclaimable after its last lease was
+	// released or expired.
+	Cooldown time.Duration
+
+	// Quotas limit how many slots a group of holders may hold at once.
+	Quotas []Quota
+
+	// Annotations are stored on the claim. An existing claim returned to the
+	// same holder keeps its original annotations.
+	Annotations map[string]string
+}
+
+// Quota limits the number of concurrent claims per holder group. Holders
+// matching Pattern are grouped by the pattern's first capture group, or by the
+// whole match if it has none. Holders that don't match are not limited.
+type Quota struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Max     int
+}
+
+// Group returns the group key of holder under this quota, and false if the
+// quota does not apply to holder.
+func (q Quota) Group(holder string) (string, bool) {
+	m := q.Pattern.FindStringSubmatch(holder)
+	if m == nil {
+		return "", false
+	}
+	if len(m) > 1 {
+		return m[1], true
+	}
+	return m[0], true
+}
+
+// CheckQuotas returns ErrQuotaExceeded if granting holder one more claim would
+// exceed any quota, given the holders of all active claims in the pool.
+func CheckQuotas(quotas []Quota, holder string, activeHolders []string) error {
+	for _, q := range quotas {
+		group, ok := q.Group(holder)
+		if !ok {
+			continue
+		}
+
+		count := 0
+		for _, h := range activeHolders {
+			if g, ok := q.Group(h); ok && g == group {
+				count++
+			}
+		}
+
+		if count >= q.Max {
+			return fmt.Errorf("%w: %q allows %d claim(s) for group %q", ErrQuotaExceeded, q.Name, q.Max, group)
+		}
+	}
+	return nil
+}
+
+// RenewExpiry computes the new expiry for renewing claim at now by ttl, capped
+// at the claim's maximum lifetime when maxLease is > 0. It returns
+// ErrMaxLeaseExceeded if the cap leaves nothing to extend.
+func RenewExpiry(claim *Claim, now time.Time, ttl, maxLease time.Duration) (time.Time, error) {
+	expiry := now.Add(ttl)
+	if maxLease <= 0 {
+		return expiry, nil
+	}
+
+	limit := claim.ClaimedAt.Add(maxLease)
+	if expiry.After(limit) {
+		if !limit.After(claim.ExpiresAt) {
+			return time.Time{}, ErrMaxLeaseExceeded
+		}
+		expiry = limit
+	}
+	return expiry, nil
+}
+
+// SlotStatus represents the state of a single slot.
+// Expired slots still carry the lapsed claim until they are reaped.
+type SlotStatus struct {
+	SlotName     string     `json:"slot_name"`
+	Claimed      bool       `json:"claimed"`
+	Expired      bool       `json:"expired,omitempty"`
+	Claim        *Claim     `json:"claim,omitempty"`
+	State        SlotState  `json:"state"`
+	Reason       string     `json:"reason,omitempty"`
+	ReleasedAt   *time.Time `json:"released_at,omitempty"`
+	CoolingUntil *time.Time `json:"cooling_until,omitempty"`
+
+	// Reservations lists the slot's current and upcoming reservations.
+	Reservations []Reservation `json:"reservations,omitempty"`
+}
+
+// Phase summarizes the slot as a single word: "quarantined", "draining",
+// "claimed", "expired", "cooling" or "free". Administrative states take
+// precedence over the lease.
+func (s *SlotStatus) Phase() string {
+	switch {
+	case s.State == SlotQuarantined:
+		return "quarantined"
+	case s.State == SlotDraining:
+		return "draining"
+	case s.Claimed:
+		return "claimed"
+	case s.Expired:
+		return "expired"
+	case s.CoolingUntil != nil:
+		return "cooling"
+	default:
+		return "free"
+	}
+}
+
+// LockStore manages exclusive leases on pool slots.
+type LockStore interface {
+	// Claim atomically acquires a free slot in the named pool.
+	// slotNames is the list of valid slot names in the pool.
+	// Slots whose lease expired aren't free until they are reaped.
+	// Draining and quarantined slots are never handed out to a new holder, nor
+	// are slots still within opts.Cooldown of their last release, nor slots
+	// whose reservation by another holder starts within ttl.
+	// A holder claiming during its own reservation always gets the reserved slot.
+	// Returns ErrQuotaExceeded if the holder's group already holds its quota of
+	// slots, or ErrPoolExhausted if no slots are available.
+	Claim(ctx context.Context, pool string, slotNames []string, holder string, ttl time.Duration, opts ClaimOptions) (*Claim, error)
+
+	// Release releases the claim identified by leaseID.
+	// Returns ErrLeaseNotFound if the lease does not exist.
+	Release(ctx context.Context, pool string, leaseID string) error
+
+	// Revoke forcibly releases the claim identified by leaseID, recording it
+	// as a revocation rather than a release by the holder.
+	// Returns ErrLeaseNotFound if the lease does not exist.
+	Revoke(ctx context.Context, pool string, leaseID string) error
+
+	// ReleaseByHolder releases the claim held by the given holder in the pool.
+	// Returns ErrLeaseNotFound if no active claim is found for the holder.
+	ReleaseByHolder(ctx context.Context, pool string, holder string) error
+
+	// FindByHolder returns the active claim held by the given holder in the pool.
+	// Returns ErrLeaseNotFound if no active claim is found for the holder.
+	FindByHolder(ctx context.Context, pool string, holder string) (*Claim, error)
+
+	// Renew extends the TTL of an existing claim.
+	// If maxLease is > 0, the expiry is capped at ClaimedAt + maxLease and
+	// ErrMaxLeaseExceeded is returned once the claim cannot be extended further.
+	// The expiry is also capped at the start of another holder's reservation,
+	// returning ErrSlotReserved if that leaves nothing to extend.
+	// Returns ErrLeaseNotFound or ErrLeaseExpired as appropriate.
+	Renew(ctx context.Context, pool string, leaseID string, ttl time.Duration, maxLease time.Duration) (*Claim, error)
+
+	// Reap clears every expired lease in the named pool and returns the claims
+	// that were removed.
+	Reap(ctx context.Context, pool string, slotNames []string) ([]Claim, error)
+
+	// Reserve books a slot in the named pool for holder during [from, until).
+	// A slot is eligible if it is active, has no overlapping reservation, and
+	// its current claim (if any) expires before from.
+	// Returns ErrReservationConflict if no slot is eligible.
+	Reserve(ctx context.Context, pool string, slotNames []string, holder string, from, until time.Time) (*Reservation, error)
+
+	// CancelReservation removes the reservation with the given ID.
+	// Returns ErrReservationNotFound if it does not exist.
+	CancelReservation(ctx context.Context, pool string, reservationID string) error
+
+	// History returns the recorded claim, renew, release, expire and revoke
+	// events of the given slots at or after since, oldest first.
+	History(ctx context.Context, pool string, slotNames []string, since time.Time) ([]Event, error)
+
+	// Status returns the status of all slots in the named pool.
+	Status(ctx context.Context, pool string, slotNames []string) ([]SlotStatus, error)
+
+	// SetSlotState changes the administrative state of a slot. The reason is
+	// kept for quarantined slots and cleared for every other state.
+	SetSlotState(ctx context.Context, pool string, slotName string, state SlotState, reason string) error
+
+	// ValidateLease checks that a lease is still valid (exists and not expired).
+	ValidateLease(ctx context.Context, pool string, leaseID string) (*Claim, error)
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// Watcher is implemented by lock stores that can push slot changes instead of
+// being polled for them.
+type Watcher interface {
+	// Watch calls fn with the status of the named slots, first as they are
+	// now and then whenever any of them changes, until ctx is done or fn
+	// returns an error. It returns fn's error, or nil once ctx is done.
+	Watch(ctx context.Context, pool string, slotNames []string, fn func([]SlotStatus) error) error
+}
@@ -0,0 +1,406 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Kashuab/claimenv/internal/audit"
+	"github.com/Kashuab/claimenv/pkg/lockstore"
+	"github.com/google/uuid"
+)
+
+// Store is a thread-safe in-memory lock store for testing and local development.
+type Store struct {
+	mu       sync.Mutex
+	slots    map[string]*lockstore.Claim // key: "{pool}-{slotName}"
+	states   map[string]slotState        // key: "{pool}-{slotName}"; absent means active
+	released map[string]time.Time        // key: "{pool}-{slotName}"; when the last lease was freed
+
+	reservations map[string][]lockstore.Reservation // key: "{pool}-{slotName}"
+	events       []lockstore.Event
+	audit        []audit.Event
+}
+
+// slotState is the administrative state recorded by SetSlotState.
+type slotState struct {
+	state  lockstore.SlotState
+	reason string
+}
+
+func New() *Store {
+	return &Store{
+		slots:    make(map[string]*lockstore.Claim),
+		states:   make(map[string]slotState),
+		released: make(map[string]time.Time),
+
+		reservations: make(map[string][]lockstore.Reservation),
+	}
+}
+
+func slotKey(pool string, slotName string) string {
+	return fmt.Sprintf("%s-%s", pool, slotName)
+}
+
+func (s *Store) Claim(_ context.Context, pool string, slotNames []string, holder string, ttl time.Duration, opts lockstore.ClaimOptions) (*lockstore.Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pruneReservations(pool, slotNames, now)
+
+	// Check if this holder already has an active claim in the pool
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if s.states[key].state == lockstore.SlotQuarantined {
+			continue
+		}
+		existing := s.slots[key]
+		if existing != nil && existing.Holder == holder && now.Before(existing.ExpiresAt) {
+			c := *existing
+			c.Existing = true
+			return &c, nil
+		}
+	}
+
+	// A holder claiming during its own reservation gets the reserved slot
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if s.states[key].state == lockstore.SlotQuarantined {
+			continue
+		}
+		if lockstore.ActiveReservation(s.reservations[key], holder, now) == nil {
+			continue
+		}
+		if s.slots[key] == nil {
+			return s.claimSlot(pool, name, holder, now, ttl, opts.Annotations), nil
+		}
+	}
+
+	var active []string
+	for _, name := range slotNames {
+		if existing := s.slots[slotKey(pool, name)]; existing != nil && now.Before(existing.ExpiresAt) {
+			active = append(active, existing.Holder)
+		}
+	}
+	if err := lockstore.CheckQuotas(opts.Quotas, holder, active); err != nil {
+		return nil, err
+	}
+
+	// Otherwise find a free slot
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if st := s.states[key].state; st == lockstore.SlotDraining || st == lockstore.SlotQuarantined {
+			continue
+		}
+		if lockstore.ConflictingReservation(s.reservations[key], holder, now, now.Add(ttl)) != nil {
+			continue
+		}
+		if s.slots[key] == nil {
+			if now.Before(s.released[key].Add(opts.Cooldown)) {
+				continue
+			}
+
+			return s.claimSlot(pool, name, holder, now, ttl, opts.Annotations), nil
+		}
+	}
+
+	return nil, lockstore.ErrPoolExhausted
+}
+
+// claimSlot records a new claim on the slot. The caller must hold s.mu.
+func (s *Store) claimSlot(pool, slotName, holder string, now time.Time, ttl time.Duration, annotations map[string]string) *lockstore.Claim {
+	claim := &lockstore.Claim{
+		Pool:        pool,
+		SlotName:    slotName,
+		LeaseID:     uuid.New().String(),
+		Holder:      holder,
+		ClaimedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		Annotations: annotations,
+	}
+
+	s.slots[slotKey(pool, slotName)] = claim
+	s.events = append(s.events, lockstore.NewEvent(lockstore.EventClaim, claim, now))
+	return claim
+}
+
+// pruneReservations drops ended reservations. The caller must hold s.mu.
+func (s *Store) pruneReservations(pool string, slotNames []string, now time.Time) {
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if rs := lockstore.PruneReservations(s.reservations[key], now); len(rs) > 0 {
+			s.reservations[key] = rs
+		} else {
+			delete(s.reservations, key)
+		}
+	}
+}
+
+func (s *Store) Release(_ context.Context, pool string, leaseID string) error {
+	return s.release(pool, leaseID, lockstore.EventRelease)
+}
+
+func (s *Store) Revoke(_ context.Context, pool string, leaseID string) error {
+	return s.release(pool, leaseID, lockstore.EventRevoke)
+}
+
+// release frees the slot holding leaseID, recording an event of the given kind.
+func (s *Store) release(pool string, leaseID string, kind lockstore.EventKind) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for key, claim := range s.slots {
+		if claim.Pool == pool && claim.LeaseID == leaseID {
+			delete(s.slots, key)
+			s.released[key] = now
+			s.events = append(s.events, lockstore.NewEvent(kind, claim, now))
+			return nil
+		}
+	}
+
+	return lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) ReleaseByHolder(_ context.Context, pool string, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for key, claim := range s.slots {
+		if claim.Pool == pool && claim.Holder == holder && now.Before(claim.ExpiresAt) {
+			delete(s.slots, key)
+			s.released[key] = now
+			s.events = append(s.events, lockstore.NewEvent(lockstore.EventRelease, claim, now))
+			return nil
+		}
+	}
+
+	return lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) FindByHolder(_ context.Context, pool string, holder string) (*lockstore.Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, claim := range s.slots {
+		if claim.Pool == pool && claim.Holder == holder && now.Before(claim.ExpiresAt) {
+			return claim, nil
+		}
+	}
+
+	return nil, lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) Renew(_ context.Context, pool string, leaseID string, ttl time.Duration, maxLease time.Duration) (*lockstore.Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, claim := range s.slots {
+		if claim.Pool == pool && claim.LeaseID == leaseID {
+			if now.After(claim.ExpiresAt) {
+				return nil, lockstore.ErrLeaseExpired
+			}
+			expiry, err := lockstore.RenewExpiry(claim, now, ttl, maxLease)
+			if err != nil {
+				return nil, err
+			}
+			rs := s.reservations[slotKey(pool, claim.SlotName)]
+			expiry, err = lockstore.CapForReservations(rs, claim.Holder, now, claim.ExpiresAt, expiry)
+			if err != nil {
+				return nil, err
+			}
+			claim.ExpiresAt = expiry
+			s.events = append(s.events, lockstore.NewEvent(lockstore.EventRenew, claim, now))
+			return claim, nil
+		}
+	}
+
+	return nil, lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) Reap(_ context.Context, pool string, slotNames []string) ([]lockstore.Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var reaped []lockstore.Claim
+
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if claim, ok := s.slots[key]; ok && now.After(claim.ExpiresAt) {
+			reaped = append(reaped, *claim)
+			delete(s.slots, key)
+			// The cooldown runs from when the lease lapsed, not from the reap
+			s.released[key] = claim.ExpiresAt
+			s.events = append(s.events, lockstore.NewEvent(lockstore.EventExpire, claim, claim.ExpiresAt))
+		}
+	}
+
+	return reaped, nil
+}
+
+func (s *Store) Reserve(_ context.Context, pool string, slotNames []string, holder string, from, until time.Time) (*lockstore.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pruneReservations(pool, slotNames, now)
+
+	for _, name := range slotNames {
+		key := slotKey(pool, name)
+		if _, ok := s.states[key]; ok {
+			continue
+		}
+		if existing := s.slots[key]; existing != nil && existing.Holder != holder && existing.ExpiresAt.After(from) {
+			continue
+		}
+		// Any overlapping reservation conflicts, including the holder's own
+		if lockstore.ConflictingReservation(s.reservations[key], "", from, until) != nil {
+			continue
+		}
+
+		r := lockstore.Reservation{
+			ID:       uuid.New().String(),
+			Pool:     pool,
+			SlotName: name,
+			Holder:   holder,
+			From:     from,
+			Until:    until,
+		}
+		s.reservations[key] = append(s.reservations[key], r)
+		return &r, nil
+	}
+
+	return nil, lockstore.ErrReservationConflict
+}
+
+func (s *Store) CancelReservation(_ context.Context, pool string, reservationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, rs := range s.reservations {
+		for i, r := range rs {
+			if r.Pool == pool && r.ID == reservationID {
+				s.reservations[key] = append(rs[:i], rs[i+1:]...)
+				return nil
+			}
+		}
+	}
+
+	return lockstore.ErrReservationNotFound
+}
+
+func (s *Store) History(_ context.Context, pool string, slotNames []string, since time.Time) ([]lockstore.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]bool, len(slotNames))
+	for _, name := range slotNames {
+		wanted[name] = true
+	}
+
+	var events []lockstore.Event
+	for _, ev := range s.events {
+		if ev.Pool == pool && wanted[ev.SlotName] && !ev.At.Before(since) {
+			events = append(events, ev)
+		}
+	}
+
+	// Expire events carry the lapse time, which can predate events recorded before them
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events, nil
+}
+
+func (s *Store) Status(_ context.Context, pool string, slotNames []string) ([]lockstore.SlotStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pruneReservations(pool, slotNames, now)
+	statuses := make([]lockstore.SlotStatus, len(slotNames))
+
+	for i, name := range slotNames {
+		key := slotKey(pool, name)
+		statuses[i] = lockstore.SlotStatus{SlotName: name, State: lockstore.SlotActive}
+
+		if st, ok := s.states[key]; ok {
+			statuses[i].State = st.state
+			statuses[i].Reason = st.reason
+		}
+
+		if claim, ok := s.slots[key]; ok {
+			statuses[i].Claimed = now.Before(claim.ExpiresAt)
+			statuses[i].Expired = !statuses[i].Claimed
+			statuses[i].Claim = claim
+		} else if releasedAt, ok := s.released[key]; ok {
+			statuses[i].ReleasedAt = &releasedAt
+		}
+
+		statuses[i].Reservations = append([]lockstore.Reservation(nil), s.reservations[key]...)
+	}
+
+	return statuses, nil
+}
+
+func (s *Store) SetSlotState(_ context.Context, pool string, slotName string, state lockstore.SlotState, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := slotKey(pool, slotName)
+	switch state {
+	case lockstore.SlotActive:
+		delete(s.states, key)
+	case lockstore.SlotQuarantined:
+		s.states[key] = slotState{state: state, reason: reason}
+	default:
+		s.states[key] = slotState{state: state}
+	}
+
+	return nil
+}
+
+func (s *Store) ValidateLease(_ context.Context, pool string, leaseID string) (*lockstore.Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, claim := range s.slots {
+		if claim.Pool == pool && claim.LeaseID == leaseID {
+			if now.After(claim.ExpiresAt) {
+				return nil, lockstore.ErrLeaseExpired
+			}
+			return claim, nil
+		}
+	}
+
+	return nil, lockstore.ErrLeaseNotFound
+}
+
+func (s *Store) AppendAudit(_ context.Context, ev audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.audit = append(s.audit, ev)
+	return nil
+}
+
+// AuditLog returns the audit events appended so far (useful for testing).
+func (s *Store) AuditLog() []audit.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]audit.Event(nil), s.audit...)
+}
+
+func (s *Store) Close() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+package lockstore
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrReservationConflict = errors.New("claimenv: no slot is free for the requested reservation window")
+	ErrReservationNotFound = errors.New("claimenv: reservation not found")
+	ErrSlotReserved        = errors.New("claimenv: slot is reserved by another holder")
+)
+
+// Reservation books a slot for a holder during a future time window. Other
+// holders can't claim the slot if their lease would overlap the window, and the
+// reserving holder's claim during the window is guaranteed to get the slot.
+type Reservation struct {
+	ID       string    `json:"id"`
+	Pool     string    `json:"pool"`
+	SlotName string    `json:"slot_name"`
+	Holder   string    `json:"holder"`
+	From     time.Time `json:"from"`
+	Until    time.Time `json:"until"`
+}
+
+// Overlaps reports whether the reservation overlaps the window [from, until).
+func (r *Reservation) Overlaps(from, until time.Time) bool {
+	return r.From.Before(until) && from.Before(r.Until)
+}
+
+// ActiveReservation returns the reservation in rs held by holder whose window
+// contains now, or nil if there is none.
+func ActiveReservation(rs []Reservation, holder string, now time.Time) *Reservation {
+	for i := range rs {
+		if rs[i].Holder == holder && !now.Before(rs[i].From) && now.Before(rs[i].Until) {
+			return &rs[i]
+		}
+	}
+	return nil
+}
+
+// ConflictingReservation returns the first reservation in rs held by someone
+// other than holder that overlaps [from, until), or nil if there is none.
+func ConflictingReservation(rs []Reservation, holder string, from, until time.Time) *Reservation {
+	for i := range rs {
+		if rs[i].Holder != holder && rs[i].Overlaps(from, until) {
+			return &rs[i]
+		}
+	}
+	return nil
+}
+
+// PruneReservations drops reservations whose window ended before now.
+func PruneReservations(rs []Reservation, now time.Time) []Reservation {
+	kept := rs[:0]
+	for _, r := range rs {
+		if now.Before(r.Until) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// CapForReservations caps a renewed expiry so the claim held by holder never
+// runs into another holder's reservation. It returns ErrSlotReserved if the
+// cap leaves nothing to extend beyond the current expiry.
+func CapForReservations(rs []Reservation, holder string, now, current, expiry time.Time) (time.Time, error) {
+	for {
+		r := ConflictingReservation(rs, holder, now, expiry)
+		if r == nil {
+			return expiry, nil
+		}
+		if !r.From.After(current) {
+			return time.Time{}, ErrSlotReserved
+		}
+		expiry = r.From
+	}
+}
@@ -0,0 +1,1883 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: claimenv/v1/claimenv.proto
+
+package claimenvv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SlotState is the administrative state of a slot.
+type SlotState int32
+
+const (
+	SlotState_SLOT_STATE_UNSPECIFIED SlotState = 0
+	// Handed out by Claim as usual.
+	SlotState_SLOT_STATE_ACTIVE SlotState = 1
+	// Keeps its current lease but accepts no new claims.
+	SlotState_SLOT_STATE_DRAINING SlotState = 2
+	// Never claimable until restored.
+	SlotState_SLOT_STATE_QUARANTINED SlotState = 3
+)
+
+// Enum value maps for SlotState.
+var (
+	SlotState_name = map[int32]string{
+		0: "SLOT_STATE_UNSPECIFIED",
+		1: "SLOT_STATE_ACTIVE",
+		2: "SLOT_STATE_DRAINING",
+		3: "SLOT_STATE_QUARANTINED",
+	}
+	SlotState_value = map[string]int32{
+		"SLOT_STATE_UNSPECIFIED": 0,
+		"SLOT_STATE_ACTIVE":      1,
+		"SLOT_STATE_DRAINING":    2,
+		"SLOT_STATE_QUARANTINED": 3,
+	}
+)
+
+func (x SlotState) Enum() *SlotState {
+	p := new(SlotState)
+	*p = x
+	return p
+}
+
+func (x SlotState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SlotState) Descriptor() protoreflect.EnumDescriptor {
+	return file_claimenv_v1_claimenv_proto_enumTypes[0].Descriptor()
+}
+
+func (SlotState) Type() protoreflect.EnumType {
+	return &file_claimenv_v1_claimenv_proto_enumTypes[0]
+}
+
+func (x SlotState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SlotState.Descriptor instead.
+func (SlotState) EnumDescriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{0}
+}
+
+// EventKind identifies what happened to a slot's lease.
+type EventKind int32
+
+const (
+	EventKind_EVENT_KIND_UNSPECIFIED EventKind = 0
+	EventKind_EVENT_KIND_CLAIM       EventKind = 1
+	EventKind_EVENT_KIND_RENEW       EventKind = 2
+	EventKind_EVENT_KIND_RELEASE     EventKind = 3
+	EventKind_EVENT_KIND_EXPIRE      EventKind = 4
+	EventKind_EVENT_KIND_REVOKE      EventKind = 5
+)
+
+// Enum value maps for EventKind.
+var (
+	EventKind_name = map[int32]string{
+		0: "EVENT_KIND_UNSPECIFIED",
+		1: "EVENT_KIND_CLAIM",
+		2: "EVENT_KIND_RENEW",
+		3: "EVENT_KIND_RELEASE",
+		4: "EVENT_KIND_EXPIRE",
+		5: "EVENT_KIND_REVOKE",
+	}
+	EventKind_value = map[string]int32{
+		"EVENT_KIND_UNSPECIFIED": 0,
+		"EVENT_KIND_CLAIM":       1,
+		"EVENT_KIND_RENEW":       2,
+		"EVENT_KIND_RELEASE":     3,
+		"EVENT_KIND_EXPIRE":      4,
+		"EVENT_KIND_REVOKE":      5,
+	}
+)
+
+func (x EventKind) Enum() *EventKind {
+	p := new(EventKind)
+	*p = x
+	return p
+}
+
+func (x EventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_claimenv_v1_claimenv_proto_enumTypes[1].Descriptor()
+}
+
+func (EventKind) Type() protoreflect.EnumType {
+	return &file_claimenv_v1_claimenv_proto_enumTypes[1]
+}
+
+func (x EventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventKind.Descriptor instead.
+func (EventKind) EnumDescriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{1}
+}
+
+// Claim is an active lease on a slot.
+type Claim struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Pool     string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	SlotName string                 `protobuf:"bytes,2,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	// Only set in the responses of Claim and Renew, for the caller's own lease.
+	LeaseId   string                 `protobuf:"bytes,3,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	Holder    string                 `protobuf:"bytes,4,opt,name=holder,proto3" json:"holder,omitempty"`
+	ClaimedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=claimed_at,json=claimedAt,proto3" json:"claimed_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Holder-supplied context such as an MR URL or branch.
+	Annotations   map[string]string `protobuf:"bytes,7,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Claim) Reset() {
+	*x = Claim{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Claim) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Claim) ProtoMessage() {}
+
+func (x *Claim) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Claim.ProtoReflect.Descriptor instead.
+func (*Claim) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Claim) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *Claim) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *Claim) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *Claim) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *Claim) GetClaimedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClaimedAt
+	}
+	return nil
+}
+
+func (x *Claim) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Claim) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+// SlotStatus is the state of a single slot.
+type SlotStatus struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SlotName string                 `protobuf:"bytes,1,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	Claimed  bool                   `protobuf:"varint,2,opt,name=claimed,proto3" json:"claimed,omitempty"`
+	// Set while a lapsed claim hasn't been reaped yet.
+	Expired bool `protobuf:"varint,3,opt,name=expired,proto3" json:"expired,omitempty"`
+	// The active or lapsed claim, if any.
+	Claim *Claim    `protobuf:"bytes,4,opt,name=claim,proto3" json:"claim,omitempty"`
+	State SlotState `protobuf:"varint,5,opt,name=state,proto3,enum=claimenv.v1.SlotState" json:"state,omitempty"`
+	// Why the slot was quarantined.
+	Reason     string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReleasedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=released_at,json=releasedAt,proto3" json:"released_at,omitempty"`
+	// Set while a free slot is within the pool's cooldown.
+	CoolingUntil *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=cooling_until,json=coolingUntil,proto3" json:"cooling_until,omitempty"`
+	// Current and upcoming reservations.
+	Reservations []*Reservation `protobuf:"bytes,9,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	// "quarantined", "draining", "claimed", "expired", "cooling" or "free".
+	Phase         string `protobuf:"bytes,10,opt,name=phase,proto3" json:"phase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotStatus) Reset() {
+	*x = SlotStatus{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotStatus) ProtoMessage() {}
+
+func (x *SlotStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotStatus.ProtoReflect.Descriptor instead.
+func (*SlotStatus) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SlotStatus) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *SlotStatus) GetClaimed() bool {
+	if x != nil {
+		return x.Claimed
+	}
+	return false
+}
+
+func (x *SlotStatus) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *SlotStatus) GetClaim() *Claim {
+	if x != nil {
+		return x.Claim
+	}
+	return nil
+}
+
+func (x *SlotStatus) GetState() SlotState {
+	if x != nil {
+		return x.State
+	}
+	return SlotState_SLOT_STATE_UNSPECIFIED
+}
+
+func (x *SlotStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SlotStatus) GetReleasedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleasedAt
+	}
+	return nil
+}
+
+func (x *SlotStatus) GetCoolingUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CoolingUntil
+	}
+	return nil
+}
+
+func (x *SlotStatus) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+func (x *SlotStatus) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+// Reservation books a slot for a holder during a time window.
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pool          string                 `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
+	SlotName      string                 `protobuf:"bytes,3,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	Holder        string                 `protobuf:"bytes,4,opt,name=holder,proto3" json:"holder,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Reservation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reservation) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *Reservation) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *Reservation) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *Reservation) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Reservation) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+// Event is an entry in a slot's claim history.
+type Event struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Pool     string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	SlotName string                 `protobuf:"bytes,2,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	Kind     EventKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=claimenv.v1.EventKind" json:"kind,omitempty"`
+	Holder   string                 `protobuf:"bytes,4,opt,name=holder,proto3" json:"holder,omitempty"`
+	// Left out of History responses.
+	LeaseId string                 `protobuf:"bytes,5,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	At      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	// The claim's annotations at the time of the event.
+	Annotations   map[string]string `protobuf:"bytes,7,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Event) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *Event) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *Event) GetKind() EventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return EventKind_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *Event) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *Event) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+func (x *Event) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *Event) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type ClaimRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Holder        string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	Annotations   map[string]string      `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClaimRequest) Reset() {
+	*x = ClaimRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClaimRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClaimRequest) ProtoMessage() {}
+
+func (x *ClaimRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClaimRequest.ProtoReflect.Descriptor instead.
+func (*ClaimRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClaimRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ClaimRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *ClaimRequest) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type ReleaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,2,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseRequest) Reset() {
+	*x = ReleaseRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRequest) ProtoMessage() {}
+
+func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReleaseRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+type ReleaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseResponse) Reset() {
+	*x = ReleaseResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseResponse) ProtoMessage() {}
+
+func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{6}
+}
+
+type RevokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	SlotName      string                 `protobuf:"bytes,2,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRequest) Reset() {
+	*x = RevokeRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRequest) ProtoMessage() {}
+
+func (x *RevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RevokeRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *RevokeRequest) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+type ReleaseByHolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Holder        string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseByHolderRequest) Reset() {
+	*x = ReleaseByHolderRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseByHolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseByHolderRequest) ProtoMessage() {}
+
+func (x *ReleaseByHolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseByHolderRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseByHolderRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReleaseByHolderRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ReleaseByHolderRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+type ReleaseByHolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseByHolderResponse) Reset() {
+	*x = ReleaseByHolderResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseByHolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseByHolderResponse) ProtoMessage() {}
+
+func (x *ReleaseByHolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseByHolderResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseByHolderResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{9}
+}
+
+type FindByHolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Holder        string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindByHolderRequest) Reset() {
+	*x = FindByHolderRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindByHolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindByHolderRequest) ProtoMessage() {}
+
+func (x *FindByHolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindByHolderRequest.ProtoReflect.Descriptor instead.
+func (*FindByHolderRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FindByHolderRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *FindByHolderRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+type RenewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,2,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewRequest) Reset() {
+	*x = RenewRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewRequest) ProtoMessage() {}
+
+func (x *RenewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewRequest.ProtoReflect.Descriptor instead.
+func (*RenewRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RenewRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *RenewRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+type ReapRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReapRequest) Reset() {
+	*x = ReapRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReapRequest) ProtoMessage() {}
+
+func (x *ReapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReapRequest.ProtoReflect.Descriptor instead.
+func (*ReapRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReapRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type ReapResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The expired claims that were cleared.
+	Claims        []*Claim `protobuf:"bytes,1,rep,name=claims,proto3" json:"claims,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReapResponse) Reset() {
+	*x = ReapResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReapResponse) ProtoMessage() {}
+
+func (x *ReapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReapResponse.ProtoReflect.Descriptor instead.
+func (*ReapResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReapResponse) GetClaims() []*Claim {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+type ReserveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Holder        string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveRequest) Reset() {
+	*x = ReserveRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRequest) ProtoMessage() {}
+
+func (x *ReserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRequest.ProtoReflect.Descriptor instead.
+func (*ReserveRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReserveRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReserveRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type CancelReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	ReservationId string                 `protobuf:"bytes,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReservationRequest) Reset() {
+	*x = CancelReservationRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReservationRequest) ProtoMessage() {}
+
+func (x *CancelReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReservationRequest.ProtoReflect.Descriptor instead.
+func (*CancelReservationRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CancelReservationRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *CancelReservationRequest) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type CancelReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReservationResponse) Reset() {
+	*x = CancelReservationResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReservationResponse) ProtoMessage() {}
+
+func (x *CancelReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReservationResponse.ProtoReflect.Descriptor instead.
+func (*CancelReservationResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{16}
+}
+
+type HistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pool  string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	// Limits the history to one slot if set.
+	SlotName      string                 `protobuf:"bytes,2,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HistoryRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type HistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HistoryResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StatusRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slots         []*SlotStatus          `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StatusResponse) GetSlots() []*SlotStatus {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+type SetSlotStateRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Pool     string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	SlotName string                 `protobuf:"bytes,2,opt,name=slot_name,json=slotName,proto3" json:"slot_name,omitempty"`
+	State    SlotState              `protobuf:"varint,3,opt,name=state,proto3,enum=claimenv.v1.SlotState" json:"state,omitempty"`
+	// Kept for quarantined slots.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSlotStateRequest) Reset() {
+	*x = SetSlotStateRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSlotStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSlotStateRequest) ProtoMessage() {}
+
+func (x *SetSlotStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSlotStateRequest.ProtoReflect.Descriptor instead.
+func (*SetSlotStateRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetSlotStateRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *SetSlotStateRequest) GetSlotName() string {
+	if x != nil {
+		return x.SlotName
+	}
+	return ""
+}
+
+func (x *SetSlotStateRequest) GetState() SlotState {
+	if x != nil {
+		return x.State
+	}
+	return SlotState_SLOT_STATE_UNSPECIFIED
+}
+
+func (x *SetSlotStateRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetSlotStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSlotStateResponse) Reset() {
+	*x = SetSlotStateResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSlotStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSlotStateResponse) ProtoMessage() {}
+
+func (x *SetSlotStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSlotStateResponse.ProtoReflect.Descriptor instead.
+func (*SetSlotStateResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{22}
+}
+
+type ValidateLeaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	LeaseId       string                 `protobuf:"bytes,2,opt,name=lease_id,json=leaseId,proto3" json:"lease_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateLeaseRequest) Reset() {
+	*x = ValidateLeaseRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateLeaseRequest) ProtoMessage() {}
+
+func (x *ValidateLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateLeaseRequest.ProtoReflect.Descriptor instead.
+func (*ValidateLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ValidateLeaseRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *ValidateLeaseRequest) GetLeaseId() string {
+	if x != nil {
+		return x.LeaseId
+	}
+	return ""
+}
+
+type WatchPoolRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPoolRequest) Reset() {
+	*x = WatchPoolRequest{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPoolRequest) ProtoMessage() {}
+
+func (x *WatchPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPoolRequest.ProtoReflect.Descriptor instead.
+func (*WatchPoolRequest) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchPoolRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+type WatchPoolResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The first response has every slot; later ones only the changed slots.
+	Slots         []*SlotStatus `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPoolResponse) Reset() {
+	*x = WatchPoolResponse{}
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPoolResponse) ProtoMessage() {}
+
+func (x *WatchPoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_claimenv_v1_claimenv_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPoolResponse.ProtoReflect.Descriptor instead.
+func (*WatchPoolResponse) Descriptor() ([]byte, []int) {
+	return file_claimenv_v1_claimenv_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WatchPoolResponse) GetSlots() []*SlotStatus {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+var File_claimenv_v1_claimenv_proto protoreflect.FileDescriptor
+
+const file_claimenv_v1_claimenv_proto_rawDesc = "" +
+	"\n" +
+	"\x1aclaimenv/v1/claimenv.proto\x12\vclaimenv.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\x02\n" +
+	"\x05Claim\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x02 \x01(\tR\bslotName\x12\x19\n" +
+	"\blease_id\x18\x03 \x01(\tR\aleaseId\x12\x16\n" +
+	"\x06holder\x18\x04 \x01(\tR\x06holder\x129\n" +
+	"\n" +
+	"claimed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tclaimedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12E\n" +
+	"\vannotations\x18\a \x03(\v2#.claimenv.v1.Claim.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9f\x03\n" +
+	"\n" +
+	"SlotStatus\x12\x1b\n" +
+	"\tslot_name\x18\x01 \x01(\tR\bslotName\x12\x18\n" +
+	"\aclaimed\x18\x02 \x01(\bR\aclaimed\x12\x18\n" +
+	"\aexpired\x18\x03 \x01(\bR\aexpired\x12(\n" +
+	"\x05claim\x18\x04 \x01(\v2\x12.claimenv.v1.ClaimR\x05claim\x12,\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x16.claimenv.v1.SlotStateR\x05state\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12;\n" +
+	"\vreleased_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"releasedAt\x12?\n" +
+	"\rcooling_until\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcoolingUntil\x12<\n" +
+	"\freservations\x18\t \x03(\v2\x18.claimenv.v1.ReservationR\freservations\x12\x14\n" +
+	"\x05phase\x18\n" +
+	" \x01(\tR\x05phase\"\xc8\x01\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04pool\x18\x02 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x03 \x01(\tR\bslotName\x12\x16\n" +
+	"\x06holder\x18\x04 \x01(\tR\x06holder\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\"\xca\x02\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x02 \x01(\tR\bslotName\x12*\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x16.claimenv.v1.EventKindR\x04kind\x12\x16\n" +
+	"\x06holder\x18\x04 \x01(\tR\x06holder\x12\x19\n" +
+	"\blease_id\x18\x05 \x01(\tR\aleaseId\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12E\n" +
+	"\vannotations\x18\a \x03(\v2#.claimenv.v1.Event.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc8\x01\n" +
+	"\fClaimRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\x12L\n" +
+	"\vannotations\x18\x03 \x03(\v2*.claimenv.v1.ClaimRequest.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"?\n" +
+	"\x0eReleaseRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x19\n" +
+	"\blease_id\x18\x02 \x01(\tR\aleaseId\"\x11\n" +
+	"\x0fReleaseResponse\"@\n" +
+	"\rRevokeRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x02 \x01(\tR\bslotName\"D\n" +
+	"\x16ReleaseByHolderRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\"\x19\n" +
+	"\x17ReleaseByHolderResponse\"A\n" +
+	"\x13FindByHolderRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\"=\n" +
+	"\fRenewRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x19\n" +
+	"\blease_id\x18\x02 \x01(\tR\aleaseId\"!\n" +
+	"\vReapRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\":\n" +
+	"\fReapResponse\x12*\n" +
+	"\x06claims\x18\x01 \x03(\v2\x12.claimenv.v1.ClaimR\x06claims\"\xa3\x01\n" +
+	"\x0eReserveRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\"U\n" +
+	"\x18CancelReservationRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12%\n" +
+	"\x0ereservation_id\x18\x02 \x01(\tR\rreservationId\"\x1b\n" +
+	"\x19CancelReservationResponse\"s\n" +
+	"\x0eHistoryRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x02 \x01(\tR\bslotName\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"=\n" +
+	"\x0fHistoryResponse\x12*\n" +
+	"\x06events\x18\x01 \x03(\v2\x12.claimenv.v1.EventR\x06events\"#\n" +
+	"\rStatusRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\"?\n" +
+	"\x0eStatusResponse\x12-\n" +
+	"\x05slots\x18\x01 \x03(\v2\x17.claimenv.v1.SlotStatusR\x05slots\"\x8c\x01\n" +
+	"\x13SetSlotStateRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tslot_name\x18\x02 \x01(\tR\bslotName\x12,\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x16.claimenv.v1.SlotStateR\x05state\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x16\n" +
+	"\x14SetSlotStateResponse\"E\n" +
+	"\x14ValidateLeaseRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x19\n" +
+	"\blease_id\x18\x02 \x01(\tR\aleaseId\"&\n" +
+	"\x10WatchPoolRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\"B\n" +
+	"\x11WatchPoolResponse\x12-\n" +
+	"\x05slots\x18\x01 \x03(\v2\x17.claimenv.v1.SlotStatusR\x05slots*s\n" +
+	"\tSlotState\x12\x1a\n" +
+	"\x16SLOT_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SLOT_STATE_ACTIVE\x10\x01\x12\x17\n" +
+	"\x13SLOT_STATE_DRAINING\x10\x02\x12\x1a\n" +
+	"\x16SLOT_STATE_QUARANTINED\x10\x03*\x99\x01\n" +
+	"\tEventKind\x12\x1a\n" +
+	"\x16EVENT_KIND_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10EVENT_KIND_CLAIM\x10\x01\x12\x14\n" +
+	"\x10EVENT_KIND_RENEW\x10\x02\x12\x16\n" +
+	"\x12EVENT_KIND_RELEASE\x10\x03\x12\x15\n" +
+	"\x11EVENT_KIND_EXPIRE\x10\x04\x12\x15\n" +
+	"\x11EVENT_KIND_REVOKE\x10\x052\xf8\a\n" +
+	"\vLockService\x126\n" +
+	"\x05Claim\x12\x19.claimenv.v1.ClaimRequest\x1a\x12.claimenv.v1.Claim\x12D\n" +
+	"\aRelease\x12\x1b.claimenv.v1.ReleaseRequest\x1a\x1c.claimenv.v1.ReleaseResponse\x128\n" +
+	"\x06Revoke\x12\x1a.claimenv.v1.RevokeRequest\x1a\x12.claimenv.v1.Claim\x12\\\n" +
+	"\x0fReleaseByHolder\x12#.claimenv.v1.ReleaseByHolderRequest\x1a$.claimenv.v1.ReleaseByHolderResponse\x12D\n" +
+	"\fFindByHolder\x12 .claimenv.v1.FindByHolderRequest\x1a\x12.claimenv.v1.Claim\x126\n" +
+	"\x05Renew\x12\x19.claimenv.v1.RenewRequest\x1a\x12.claimenv.v1.Claim\x12;\n" +
+	"\x04Reap\x12\x18.claimenv.v1.ReapRequest\x1a\x19.claimenv.v1.ReapResponse\x12@\n" +
+	"\aReserve\x12\x1b.claimenv.v1.ReserveRequest\x1a\x18.claimenv.v1.Reservation\x12b\n" +
+	"\x11CancelReservation\x12%.claimenv.v1.CancelReservationRequest\x1a&.claimenv.v1.CancelReservationResponse\x12D\n" +
+	"\aHistory\x12\x1b.claimenv.v1.HistoryRequest\x1a\x1c.claimenv.v1.HistoryResponse\x12A\n" +
+	"\x06Status\x12\x1a.claimenv.v1.StatusRequest\x1a\x1b.claimenv.v1.StatusResponse\x12S\n" +
+	"\fSetSlotState\x12 .claimenv.v1.SetSlotStateRequest\x1a!.claimenv.v1.SetSlotStateResponse\x12F\n" +
+	"\rValidateLease\x12!.claimenv.v1.ValidateLeaseRequest\x1a\x12.claimenv.v1.Claim\x12L\n" +
+	"\tWatchPool\x12\x1d.claimenv.v1.WatchPoolRequest\x1a\x1e.claimenv.v1.WatchPoolResponse0\x01B>Z<github.com/Kashuab/claimenv/pkg/proto/claimenv/v1;claimenvv1b\x06proto3"
+
+var (
+	file_claimenv_v1_claimenv_proto_rawDescOnce sync.Once
+	file_claimenv_v1_claimenv_proto_rawDescData []byte
+)
+
+func file_claimenv_v1_claimenv_proto_rawDescGZIP() []byte {
+	file_claimenv_v1_claimenv_proto_rawDescOnce.Do(func() {
+		file_claimenv_v1_claimenv_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_claimenv_v1_claimenv_proto_rawDesc), len(file_claimenv_v1_claimenv_proto_rawDesc)))
+	})
+	return file_claimenv_v1_claimenv_proto_rawDescData
+}
+
+var file_claimenv_v1_claimenv_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_claimenv_v1_claimenv_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_claimenv_v1_claimenv_proto_goTypes = []any{
+	(SlotState)(0),                    // 0: claimenv.v1.SlotState
+	(EventKind)(0),                    // 1: claimenv.v1.EventKind
+	(*Claim)(nil),                     // 2: claimenv.v1.Claim
+	(*SlotStatus)(nil),                // 3: claimenv.v1.SlotStatus
+	(*Reservation)(nil),               // 4: claimenv.v1.Reservation
+	(*Event)(nil),                     // 5: claimenv.v1.Event
+	(*ClaimRequest)(nil),              // 6: claimenv.v1.ClaimRequest
+	(*ReleaseRequest)(nil),            // 7: claimenv.v1.ReleaseRequest
+	(*ReleaseResponse)(nil),           // 8: claimenv.v1.ReleaseResponse
+	(*RevokeRequest)(nil),             // 9: claimenv.v1.RevokeRequest
+	(*ReleaseByHolderRequest)(nil),    // 10: claimenv.v1.ReleaseByHolderRequest
+	(*ReleaseByHolderResponse)(nil),   // 11: claimenv.v1.ReleaseByHolderResponse
+	(*FindByHolderRequest)(nil),       // 12: claimenv.v1.FindByHolderRequest
+	(*RenewRequest)(nil),              // 13: claimenv.v1.RenewRequest
+	(*ReapRequest)(nil),               // 14: claimenv.v1.ReapRequest
+	(*ReapResponse)(nil),              // 15: claimenv.v1.ReapResponse
+	(*ReserveRequest)(nil),            // 16: claimenv.v1.ReserveRequest
+	(*CancelReservationRequest)(nil),  // 17: claimenv.v1.CancelReservationRequest
+	(*CancelReservationResponse)(nil), // 18: claimenv.v1.CancelReservationResponse
+	(*HistoryRequest)(nil),            // 19: claimenv.v1.HistoryRequest
+	(*HistoryResponse)(nil),           // 20: claimenv.v1.HistoryResponse
+	(*StatusRequest)(nil),             // 21: claimenv.v1.StatusRequest
+	(*StatusResponse)(nil),            // 22: claimenv.v1.StatusResponse
+	(*SetSlotStateRequest)(nil),       // 23: claimenv.v1.SetSlotStateRequest
+	(*SetSlotStateResponse)(nil),      // 24: claimenv.v1.SetSlotStateResponse
+	(*ValidateLeaseRequest)(nil),      // 25: claimenv.v1.ValidateLeaseRequest
+	(*WatchPoolRequest)(nil),          // 26: claimenv.v1.WatchPoolRequest
+	(*WatchPoolResponse)(nil),         // 27: claimenv.v1.WatchPoolResponse
+	nil,                               // 28: claimenv.v1.Claim.AnnotationsEntry
+	nil,                               // 29: claimenv.v1.Event.AnnotationsEntry
+	nil,                               // 30: claimenv.v1.ClaimRequest.AnnotationsEntry
+	(*timestamppb.Timestamp)(nil),     // 31: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),       // 32: google.protobuf.Duration
+}
+var file_claimenv_v1_claimenv_proto_depIdxs = []int32{
+	31, // 0: claimenv.v1.Claim.claimed_at:type_name -> google.protobuf.Timestamp
+	31, // 1: claimenv.v1.Claim.expires_at:type_name -> google.protobuf.Timestamp
+	28, // 2: claimenv.v1.Claim.annotations:type_name -> claimenv.v1.Claim.AnnotationsEntry
+	2,  // 3: claimenv.v1.SlotStatus.claim:type_name -> claimenv.v1.Claim
+	0,  // 4: claimenv.v1.SlotStatus.state:type_name -> claimenv.v1.SlotState
+	31, // 5: claimenv.v1.SlotStatus.released_at:type_name -> google.protobuf.Timestamp
+	31, // 6: claimenv.v1.SlotStatus.cooling_until:type_name -> google.protobuf.Timestamp
+	4,  // 7: claimenv.v1.SlotStatus.reservations:type_name -> claimenv.v1.Reservation
+	31, // 8: claimenv.v1.Reservation.from:type_name -> google.protobuf.Timestamp
+	31, // 9: claimenv.v1.Reservation.until:type_name -> google.protobuf.Timestamp
+	1,  // 10: claimenv.v1.Event.kind:type_name -> claimenv.v1.EventKind
+	31, // 11: claimenv.v1.Event.at:type_name -> google.protobuf.Timestamp
+	29, // 12: claimenv.v1.Event.annotations:type_name -> claimenv.v1.Event.AnnotationsEntry
+	30, // 13: claimenv.v1.ClaimRequest.annotations:type_name -> claimenv.v1.ClaimRequest.AnnotationsEntry
+	2,  // 14: claimenv.v1.ReapResponse.claims:type_name -> claimenv.v1.Claim
+	31, // 15: claimenv.v1.ReserveRequest.from:type_name -> google.protobuf.Timestamp
+	32, // 16: claimenv.v1.ReserveRequest.duration:type_name -> google.protobuf.Duration
+	31, // 17: claimenv.v1.HistoryRequest.since:type_name -> google.protobuf.Timestamp
+	5,  // 18: claimenv.v1.HistoryResponse.events:type_name -> claimenv.v1.Event
+	3,  // 19: claimenv.v1.StatusResponse.slots:type_name -> claimenv.v1.SlotStatus
+	0,  // 20: claimenv.v1.SetSlotStateRequest.state:type_name -> claimenv.v1.SlotState
+	3,  // 21: claimenv.v1.WatchPoolResponse.slots:type_name -> claimenv.v1.SlotStatus
+	6,  // 22: claimenv.v1.LockService.Claim:input_type -> claimenv.v1.ClaimRequest
+	7,  // 23: claimenv.v1.LockService.Release:input_type -> claimenv.v1.ReleaseRequest
+	9,  // 24: claimenv.v1.LockService.Revoke:input_type -> claimenv.v1.RevokeRequest
+	10, // 25: claimenv.v1.LockService.ReleaseByHolder:input_type -> claimenv.v1.ReleaseByHolderRequest
+	12, // 26: claimenv.v1.LockService.FindByHolder:input_type -> claimenv.v1.FindByHolderRequest
+	13, // 27: claimenv.v1.LockService.Renew:input_type -> claimenv.v1.RenewRequest
+	14, // 28: claimenv.v1.LockService.Reap:input_type -> claimenv.v1.ReapRequest
+	16, // 29: claimenv.v1.LockService.Reserve:input_type -> claimenv.v1.ReserveRequest
+	17, // 30: claimenv.v1.LockService.CancelReservation:input_type -> claimenv.v1.CancelReservationRequest
+	19, // 31: claimenv.v1.LockService.History:input_type -> claimenv.v1.HistoryRequest
+	21, // 32: claimenv.v1.LockService.Status:input_type -> claimenv.v1.StatusRequest
+	23, // 33: claimenv.v1.LockService.SetSlotState:input_type -> claimenv.v1.SetSlotStateRequest
+	25, // 34: claimenv.v1.LockService.ValidateLease:input_type -> claimenv.v1.ValidateLeaseRequest
+	26, // 35: claimenv.v1.LockService.WatchPool:input_type -> claimenv.v1.WatchPoolRequest
+	2,  // 36: claimenv.v1.LockService.Claim:output_type -> claimenv.v1.Claim
+	8,  // 37: claimenv.v1.LockService.Release:output_type -> claimenv.v1.ReleaseResponse
+	2,  // 38: claimenv.v1.LockService.Revoke:output_type -> claimenv.v1.Claim
+	11, // 39: claimenv.v1.LockService.ReleaseByHolder:output_type -> claimenv.v1.ReleaseByHolderResponse
+	2,  // 40: claimenv.v1.LockService.FindByHolder:output_type -> claimenv.v1.Claim
+	2,  // 41: claimenv.v1.LockService.Renew:output_type -> claimenv.v1.Claim
+	15, // 42: claimenv.v1.LockService.Reap:output_type -> claimenv.v1.ReapResponse
+	4,  // 43: claimenv.v1.LockService.Reserve:output_type -> claimenv.v1.Reservation
+	18, // 44: claimenv.v1.LockService.CancelReservation:output_type -> claimenv.v1.CancelReservationResponse
+	20, // 45: claimenv.v1.LockService.History:output_type -> claimenv.v1.HistoryResponse
+	22, // 46: claimenv.v1.LockService.Status:output_type -> claimenv.v1.StatusResponse
+	24, // 47: claimenv.v1.LockService.SetSlotState:output_type -> claimenv.v1.SetSlotStateResponse
+	2,  // 48: claimenv.v1.LockService.ValidateLease:output_type -> claimenv.v1.Claim
+	27, // 49: claimenv.v1.LockService.WatchPool:output_type -> claimenv.v1.WatchPoolResponse
+	36, // [36:50] is the sub-list for method output_type
+	22, // [22:36] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_claimenv_v1_claimenv_proto_init() }
+func file_claimenv_v1_claimenv_proto_init() {
+	if File_claimenv_v1_claimenv_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_claimenv_v1_claimenv_proto_rawDesc), len(file_claimenv_v1_claimenv_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_claimenv_v1_claimenv_proto_goTypes,
+		DependencyIndexes: file_claimenv_v1_claimenv_proto_depIdxs,
+		EnumInfos:         file_claimenv_v1_claimenv_proto_enumTypes,
+		MessageInfos:      file_claimenv_v1_claimenv_proto_msgTypes,
+	}.Build()
+	File_claimenv_v1_claimenv_proto = out.File
+	file_claimenv_v1_claimenv_proto_goTypes = nil
+	file_claimenv_v1_claimenv_proto_depIdxs = nil
+}
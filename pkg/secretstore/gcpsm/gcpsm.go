@@ -6,11 +6,16 @@ import (
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
-	"github.com/Kashuab/claimenv/internal/secretstore"
+	"github.com/Kashuab/claimenv/internal/tracing"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+var tracer = otel.Tracer("github.com/Kashuab/claimenv/pkg/secretstore/gcpsm")
+
 // Store implements secretstore.SecretStore using GCP Secret Manager.
 // Each secret holds a single string value.
 type Store struct {
@@ -60,7 +65,10 @@ func (s *Store) ensureSecret(ctx context.Context, secretName string) error {
 	return nil
 }
 
-func (s *Store) Read(ctx context.Context, secretName string) (string, error) {
+func (s *Store) Read(ctx context.Context, secretName string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "gcpsm.Read", trace.WithAttributes(tracing.SecretKey.String(secretName)))
+	defer tracing.End(span, &err)
+
 	result, err := s.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
 		Name: s.secretResource(secretName),
 	})
@@ -74,11 +82,14 @@ func (s *Store) Read(ctx context.Context, secretName string) (string, error) {
 	return string(result.Payload.Data), nil
 }
 
-func (s *Store) Write(ctx context.Context, secretName string, value string) error {
+func (s *Store) Write(ctx context.Context, secretName string, value string) (err error) {
+	ctx, span := tracer.Start(ctx, "gcpsm.Write", trace.WithAttributes(tracing.SecretKey.String(secretName)))
+	defer tracing.End(span, &err)
+
 	payload := []byte(value)
 
 	// Try to add a version; if the secret doesn't exist, create it first
-	_, err := s.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+	_, err = s.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
 		Parent: s.parentResource(secretName),
 		Payload: &secretmanagerpb.SecretPayload{
 			Data: payload,
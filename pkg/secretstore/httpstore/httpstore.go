@@ -0,0 +1,63 @@
+// Package httpstore implements secretstore.SecretStore by talking to a
+// "claimenv serve" instance.
+package httpstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Kashuab/claimenv/internal/api"
+	"github.com/Kashuab/claimenv/pkg/lease"
+)
+
+// Store implements secretstore.SecretStore against a claimenv server. The
+// server only serves secrets of an active lease, so every access must be made
+// with the lease in its context (see lease.NewContext), as the engine does.
+type Store struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Store {
+	return &Store{client: client}
+}
+
+// keyFor returns the lease in ctx and the env var key whose secret is secretName.
+func keyFor(ctx context.Context, secretName string) (*lease.LeaseFile, string, error) {
+	lf := lease.FromContext(ctx)
+	if lf == nil {
+		return nil, "", fmt.Errorf("the http secret backend needs a lease to access secret %q", secretName)
+	}
+	for key, name := range lf.Secrets {
+		if name == secretName {
+			return lf, key, nil
+		}
+	}
+	return nil, "", fmt.Errorf("secret %q does not belong to slot %q of the lease", secretName, lf.SlotName)
+}
+
+func (s *Store) Read(ctx context.Context, secretName string) (string, error) {
+	lf, key, err := keyFor(ctx, secretName)
+	if err != nil {
+		return "", err
+	}
+
+	var v api.Value
+	if err := s.client.Do(ctx, http.MethodGet, api.PoolPath(lf.Pool, "lease", "env", key), lf.LeaseID, nil, &v); err != nil {
+		return "", err
+	}
+	return v.Value, nil
+}
+
+func (s *Store) Write(ctx context.Context, secretName string, value string) error {
+	lf, key, err := keyFor(ctx, secretName)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(ctx, http.MethodPut, api.PoolPath(lf.Pool, "lease", "env", key), lf.LeaseID, api.Value{Value: value}, nil)
+}
+
+func (s *Store) Close() error {
+	return nil
+}
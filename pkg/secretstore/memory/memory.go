@@ -4,7 +4,7 @@ import (
 	"context"
 	"sync"
 
-	"github.com/Kashuab/claimenv/internal/secretstore"
+	"github.com/Kashuab/claimenv/pkg/secretstore"
 )
 
 // Store is a thread-safe in-memory secret store for testing and local development.